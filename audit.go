@@ -0,0 +1,298 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// LinkProblemType describes what's wrong with a record found by an audit.
+type LinkProblemType int
+
+const (
+	// LinkProblemHalfLink is a record whose peer has no matching record, a controller without a responder or the other
+	// way around.
+	LinkProblemHalfLink LinkProblemType = iota
+	// LinkProblemOrphan is a record for a device that isn't known, usually one that's been removed from the network.
+	LinkProblemOrphan
+	// LinkProblemDuplicate is a record that repeats an earlier one for the same address, group and role.
+	LinkProblemDuplicate
+)
+
+func (t LinkProblemType) String() string {
+	switch t {
+	case LinkProblemHalfLink:
+		return "Half Link"
+	case LinkProblemOrphan:
+		return "Orphan"
+	case LinkProblemDuplicate:
+		return "Duplicate"
+	default:
+		return "Unknown"
+	}
+}
+
+// LinkProblem is a record found by an audit that's inconsistent with the rest of the network.
+type LinkProblem struct {
+	Type LinkProblemType
+	// Owner is the device, or modem, whose database holds the record.
+	Owner Address
+	// Kind is what sort of owner it is, which decides how the record reads.
+	Kind LinkOwner
+	// MemAddr is where the record is kept, or zero for modems that don't report it.
+	MemAddr uint16
+	Record  *AllLinkRecord
+}
+
+func (p *LinkProblem) String() string {
+	return fmt.Sprintf("%s: Owner=%s, Record=%04X, %s", p.Type, p.Owner, p.MemAddr, p.Record.Format(p.Kind))
+}
+
+// AuditResult is what an audit found.
+type AuditResult struct {
+	// Modem is the modem's address.
+	Modem    Address
+	Problems []*LinkProblem
+	// Unreachable holds the devices whose databases couldn't be read and why. Their records, and the halves of links
+	// that point at them, aren't checked.
+	Unreachable map[Address]error
+}
+
+// RepairMode decides how a repair deals with half links.
+type RepairMode int
+
+const (
+	// RepairComplete adds the missing half of each half link.
+	RepairComplete RepairMode = iota
+	// RepairRemove deletes the half of each half link that's there.
+	RepairRemove
+)
+
+// Auditor cross-checks the All-Link databases of the modem and a set of known devices. Every controller record should
+// have a matching responder record on its peer and the other way around, no record should repeat another and every
+// record should point at a known device.
+type Auditor struct {
+	links   *LinkManager
+	devices []Address
+	// ResponderData is the data of the responder records added to complete half links, full on at the default ramp
+	// rate for the first button unless changed.
+	ResponderData [3]byte
+}
+
+// NewAuditor creates an auditor of the modem and devices, which are all the devices that should be on the network.
+// Records for any other device are reported as orphans.
+func NewAuditor(links *LinkManager, devices ...Address) *Auditor {
+	return &Auditor{links: links, devices: devices, ResponderData: defaultResponderData}
+}
+
+// auditKey identifies the link a record is half of, from the point of view of the record's owner.
+type auditKey struct {
+	peer       Address
+	group      byte
+	controller bool
+}
+
+// auditRecord is a record read during an audit and where it's kept.
+type auditRecord struct {
+	memAddr uint16
+	rec     *AllLinkRecord
+}
+
+// Audit reads the databases of the modem and every known device and reports the problems it finds. Devices that
+// can't be read, such as sleeping battery devices, are noted in the result rather than failing the audit.
+func (a *Auditor) Audit(ctx context.Context) (*AuditResult, error) {
+	modem, modemRecords, err := a.links.reloadModem(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AuditResult{Modem: modem, Unreachable: make(map[Address]error)}
+
+	owners := []Address{modem}
+	databases := map[Address][]*auditRecord{modem: modemRecords}
+
+	for _, addr := range a.devices {
+		records, err := a.links.Device(addr).GetDatabase(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			result.Unreachable[addr] = err
+
+			continue
+		}
+
+		owners = append(owners, addr)
+		databases[addr] = deviceAuditRecords(records)
+	}
+
+	// Index what each owner has so the other half of each link can be looked up.
+	links := make(map[Address]map[auditKey]bool, len(databases))
+
+	for owner, records := range databases {
+		links[owner] = make(map[auditKey]bool)
+
+		for _, r := range records {
+			links[owner][auditKey{r.rec.Address, r.rec.Group, r.rec.Flags.Controller()}] = true
+		}
+	}
+
+	known := map[Address]bool{modem: true}
+	for _, addr := range a.devices {
+		known[addr] = true
+	}
+
+	for _, owner := range owners {
+		seen := make(map[auditKey]bool)
+
+		kind := DeviceOwner(CategoryUnassigned)
+		if owner == modem {
+			kind = ModemOwner
+		}
+
+		for _, r := range databases[owner] {
+			problem := &LinkProblem{Owner: owner, Kind: kind, MemAddr: r.memAddr, Record: r.rec}
+			key := auditKey{r.rec.Address, r.rec.Group, r.rec.Flags.Controller()}
+
+			_, unreachable := result.Unreachable[key.peer]
+
+			switch {
+			case seen[key]:
+				problem.Type = LinkProblemDuplicate
+			case !known[key.peer]:
+				problem.Type = LinkProblemOrphan
+			case unreachable:
+				seen[key] = true
+
+				continue
+			case !links[key.peer][auditKey{owner, key.group, !key.controller}]:
+				problem.Type = LinkProblemHalfLink
+			default:
+				seen[key] = true
+
+				continue
+			}
+
+			seen[key] = true
+			result.Problems = append(result.Problems, problem)
+		}
+	}
+
+	return result, nil
+}
+
+// Repair fixes the problems found by an audit. Orphans and duplicates are deleted and half links are either completed
+// or deleted depending on mode. It stops at the first problem it can't fix.
+func (a *Auditor) Repair(ctx context.Context, result *AuditResult, mode RepairMode) error {
+	m := a.links
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, problem := range result.Problems {
+		var err error
+
+		rec := problem.Record
+
+		switch {
+		case problem.Type != LinkProblemHalfLink || mode == RepairRemove:
+			err = m.removeAt(ctx, problem.Owner, problem.MemAddr, rec)
+		case rec.Flags.Controller():
+			data := a.ResponderData
+			if rec.Address == result.Modem {
+				data = [3]byte{}
+			}
+
+			_, err = m.ensure(ctx, rec.Address, problem.Owner, rec.Group, false, data)
+		default:
+			var data [3]byte
+
+			data, err = m.controllerData(ctx, rec.Address == result.Modem, problem.Owner, rec.Group)
+			if err == nil {
+				_, err = m.ensure(ctx, rec.Address, problem.Owner, rec.Group, true, data)
+			}
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "unable to repair %s", problem)
+		}
+	}
+
+	return nil
+}
+
+// reloadModem reads the modem's database again, returning the modem's address and the records in use.
+func (m *LinkManager) reloadModem(ctx context.Context) (Address, []*auditRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	modem, err := m.modemAddress(ctx)
+	if err != nil {
+		return Address{}, nil, err
+	}
+
+	m.modemDB = nil
+
+	db, err := m.modemLinkDB(ctx)
+	if err != nil {
+		return Address{}, nil, err
+	}
+
+	var records []*auditRecord
+
+	for _, r := range db.records {
+		if r.rec.Flags.InUse() {
+			rec := *r.rec
+			records = append(records, &auditRecord{memAddr: r.memAddr, rec: &rec})
+		}
+	}
+
+	return modem, records, nil
+}
+
+// removeAt removes owner's record at memAddr, or when that isn't known, the first record matching rec.
+func (m *LinkManager) removeAt(ctx context.Context, owner Address, memAddr uint16, rec *AllLinkRecord) error {
+	modem, err := m.isModem(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	if modem || memAddr == 0 {
+		return m.remove(ctx, owner, rec.Address, rec.Group, rec.Flags.Controller())
+	}
+
+	db, err := m.device(owner).GetLinkDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	if got, ok := db.records[memAddr]; !ok || !got.Flags.InUse() || got.Address != rec.Address ||
+		got.Group != rec.Group {
+		// The record has already gone.
+		return nil
+	}
+
+	if err := db.deleteAt(memAddr); err != nil {
+		return err
+	}
+
+	return db.Apply(ctx)
+}
+
+// deviceAuditRecords returns the records of a device's database in use, in the order they're kept.
+func deviceAuditRecords(db map[uint16]*AllLinkRecord) []*auditRecord {
+	var records []*auditRecord
+
+	for memAddr, rec := range db {
+		if rec.Flags.InUse() {
+			records = append(records, &auditRecord{memAddr: memAddr, rec: rec})
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].memAddr > records[j].memAddr })
+
+	return records
+}
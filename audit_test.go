@@ -0,0 +1,91 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+	mock    *InsteonHubMock
+	auditor *insteon.Auditor
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.auditor = insteon.NewAuditor(insteon.NewLinkManager(hub), insteon.Address{0xAA, 0xBB, 0xCC})
+}
+
+func (s *AuditTestSuite) TestAuditRepair() {
+	modem, device := insteon.Address{0x01, 0x02, 0x03}, insteon.Address{0xAA, 0xBB, 0xCC}
+	responder := []byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01}
+	controller := []byte{0x0F, 0xF7, 0xE2, 0x01, 0x01, 0x02, 0x03, 0x00, 0x00, 0x01}
+	duplicate := []byte{0x0F, 0xEF, 0xE2, 0x01, 0x01, 0x02, 0x03, 0x00, 0x00, 0x01}
+
+	// The modem controls the device, which is fine, and a device that's gone.
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xF0},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF0, 0x06,
+			0x02, 0x59, 0x1F, 0xF0, 0xE2, 0x02, 0x99, 0x88, 0x77, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xE8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xE8, 0x06,
+			0x02, 0x59, 0x1F, 0xE8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	)
+	// The device controls the modem twice over, which has no record of it.
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device, responder, controller, duplicate)
+
+	result, err := s.auditor.Audit(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(modem, result.Modem)
+	s.Require().Empty(result.Unreachable)
+	s.Require().Len(result.Problems, 3)
+
+	s.Require().Equal(insteon.LinkProblemOrphan, result.Problems[0].Type)
+	s.Require().Equal(modem, result.Problems[0].Owner)
+	s.Require().Equal(uint16(0x1FF0), result.Problems[0].MemAddr)
+
+	s.Require().Equal(insteon.LinkProblemHalfLink, result.Problems[1].Type)
+	s.Require().Equal(device, result.Problems[1].Owner)
+	s.Require().Equal(uint16(0x0FF7), result.Problems[1].MemAddr)
+
+	s.Require().Equal(insteon.LinkProblemDuplicate, result.Problems[2].Type)
+	s.Require().Equal(device, result.Problems[2].Owner)
+	s.Require().Equal(uint16(0x0FEF), result.Problems[2].MemAddr)
+
+	// The orphan is cleared in place, the modem gets the missing responder half and the duplicate is deleted.
+	deleted := []byte{0x0F, 0xEF, 0x62, 0x01, 0x01, 0x02, 0x03, 0x00, 0x00, 0x01}
+
+	s.mock.Expect(
+		[]byte{0x02, 0x76, 0x1F, 0xF0, 0x62, 0x02, 0x99, 0x88, 0x77, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x76, 0x1F, 0xF0, 0x62, 0x02, 0x99, 0x88, 0x77, 0x00, 0x00, 0x00, 0x06},
+		[]byte{0x02, 0x6F, 0x41, 0x82, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x6F, 0x41, 0x82, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+	)
+	s.mock.ExpectDatabase(device, responder, controller, duplicate)
+	s.mock.ExpectRecordWrite(device, deleted, deleted)
+
+	s.Require().NoError(s.auditor.Repair(s.mock.ctx, result, insteon.RepairComplete))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(AuditTestSuite))
+}
@@ -0,0 +1,127 @@
+package insteon
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AwakeWindow is how long a battery powered device keeps listening after it transmits. Commands for these devices are
+// held until they announce they're awake and then sent within this window.
+const AwakeWindow = 4 * time.Second
+
+type awakeOp struct {
+	// ctx is the caller's context, the op is skipped or cancelled when it's done.
+	ctx  context.Context
+	run  func(context.Context) error
+	done chan error
+}
+
+// awakeQueue holds operations for a battery powered device until the device is awake.
+type awakeQueue struct {
+	mu      sync.Mutex
+	pending []*awakeOp
+	running bool
+}
+
+// do queues op to run the next time the device is awake and waits for it to complete.
+func (q *awakeQueue) do(ctx context.Context, op func(context.Context) error) error {
+	qop := &awakeOp{ctx: ctx, run: op, done: make(chan error, 1)}
+
+	q.mu.Lock()
+	q.pending = append(q.pending, qop)
+	q.mu.Unlock()
+
+	select {
+	case err := <-qop.done:
+		return err
+	case <-ctx.Done():
+		if q.remove(qop) {
+			return ctx.Err()
+		}
+
+		// The op has already started, so report how it actually ended rather than guessing.
+		return <-qop.done
+	}
+}
+
+// len returns the number of operations waiting for the device to wake up.
+func (q *awakeQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// remove takes op out of the queue, returning false if it was no longer waiting.
+func (q *awakeQueue) remove(op *awakeOp) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for idx, p := range q.pending {
+		if p == op {
+			q.pending = append(q.pending[:idx], q.pending[idx+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// awake runs the queued operations in order until the queue is empty or the awake window closes. Anything left over
+// waits for the next time the device wakes up.
+func (q *awakeQueue) awake() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.running || len(q.pending) == 0 {
+		return
+	}
+
+	q.running = true
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), AwakeWindow)
+		defer cancel()
+
+		for {
+			q.mu.Lock()
+			if len(q.pending) == 0 || ctx.Err() != nil {
+				q.running = false
+				q.mu.Unlock()
+
+				return
+			}
+
+			op := q.pending[0]
+			q.pending = q.pending[1:]
+			q.mu.Unlock()
+
+			op.done <- op.runWithin(ctx)
+		}
+	}()
+}
+
+// runWithin runs the op with a context that's cancelled when either the awake window or the caller's context is done.
+func (op *awakeOp) runWithin(window context.Context) error {
+	if err := op.ctx.Err(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(window)
+	defer cancel()
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		select {
+		case <-op.ctx.Done():
+			cancel()
+		case <-stop:
+		}
+	}()
+
+	return op.run(ctx)
+}
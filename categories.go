@@ -121,6 +121,12 @@ var products = map[Category]map[SubCategory]Product{
 		0x0B: Product{ProductKey: 0x000022, Description: "Access Point [2443]"},
 		0x0C: Product{ProductKey: 0x000028, Description: "IES Color Touchscreen"},
 		0x0D: Product{ProductKey: 0x00004D, Description: "SmartLabs KeyFOB [????]"},
+		0x10: Product{ProductKey: 0x000000, Description: "RemoteLinc 2 Keypad, 4 Scene [2444A2xx4]"},
+		0x11: Product{ProductKey: 0x000000, Description: "RemoteLinc 2 Switch [2444A3xx]"},
+		0x12: Product{ProductKey: 0x000000, Description: "RemoteLinc 2 Keypad, 8 Scene [2444A2xx8]"},
+		0x14: Product{ProductKey: 0x000000, Description: "Mini Remote, 4 Scene [2342-432]"},
+		0x15: Product{ProductKey: 0x000000, Description: "Mini Remote, Switch [2342-442]"},
+		0x16: Product{ProductKey: 0x000000, Description: "Mini Remote, 8 Scene [2342-422]"},
 	},
 	CategoryDimmableLighting: {
 		0x00: Product{ProductKey: 0x000000, Description: "LampLinc V2 [2456D3]"},
@@ -146,6 +152,7 @@ var products = map[Category]map[SubCategory]Product{
 		0x1B: Product{ProductKey: 0x000050, Description: "KeypadLinc 6-button Dimmer [2486DWH6]"},
 		0x1C: Product{ProductKey: 0x000051, Description: "KeypadLinc 8-button Dimmer [2486DWH8]"},
 		0x1D: Product{ProductKey: 0x000052, Description: "SwitchLinc Dimmer 1200W [2476D]"},
+		0x2E: Product{ProductKey: 0x000000, Description: "FanLinc [2475F]"},
 		0x3a: Product{ProductKey: 0x0, Description: "LED Bulb [2672-222]"},
 	},
 	CategorySwitchedLighting: {
@@ -167,6 +174,7 @@ var products = map[Category]map[SubCategory]Product{
 		0x14: Product{ProductKey: 0x000045, Description: "In-LineLinc Relay with Sense [2475S2]"},
 		0x15: Product{ProductKey: 0x000047, Description: "SwitchLinc Relay with Sense [2476S2]"},
 		0x1a: Product{ProductKey: 0x000000, Description: "ToggleLinc On/Off Switch [2466SW]"},
+		0x39: Product{ProductKey: 0x000000, Description: "On/Off Outlet [2663-222]"},
 	},
 	CategoryNetworkBridge: {
 		0x01: Product{ProductKey: 0x000000, Description: "PowerLinc Serial [2414S]"},
@@ -193,6 +201,7 @@ var products = map[Category]map[SubCategory]Product{
 	},
 	CategoryIrrigation: {
 		0x00: Product{ProductKey: 0x000001, Description: "Compacta EZRain Sprinkler Controller"},
+		0x01: Product{ProductKey: 0x000000, Description: "Compacta EZFlora Sprinkler Controller"},
 	},
 	CategoryClimate: {
 		0x00: Product{ProductKey: 0x000000, Description: "Broan SMSC080 Exhaust Fan"},
@@ -202,6 +211,8 @@ var products = map[Category]map[SubCategory]Product{
 		0x04: Product{ProductKey: 0x000024, Description: "Compacta EZThermx Thermostat"},
 		0x05: Product{ProductKey: 0x000038, Description: "Broan, Venmar, BEST Rangehoods"},
 		0x06: Product{ProductKey: 0x000043, Description: "Broan SmartSense Make-up Damper"},
+		0x0A: Product{ProductKey: 0x000000, Description: "Wireless Thermostat [2441ZTH]"},
+		0x0B: Product{ProductKey: 0x000000, Description: "Thermostat [2441TH]"},
 	},
 	CategoryPoolAndSpa: {
 		0x00: Product{ProductKey: 0x000003, Description: "Compacta EZPool"},
@@ -228,6 +239,7 @@ var products = map[Category]map[SubCategory]Product{
 		0x04: Product{ProductKey: 0x000026, Description: "Energy Inc. TED 5000 Gateway - USB"},
 		0x05: Product{ProductKey: 0x00002a, Description: "Energy Inc. TED 5000 Gateway - Ethernet"},
 		0x06: Product{ProductKey: 0x00002b, Description: "Energy Inc. TED 3000 Three Phase Measuring Transmitting Unit (MTU)"},
+		0x07: Product{ProductKey: 0x000000, Description: "iMeter Solo [2423A1]"},
 	},
 	CategoryWindowCovering: {
 		0x00: Product{ProductKey: 0x00000B, Description: "Somfy Drape Controller RF Bridge"},
@@ -237,10 +249,13 @@ var products = map[Category]map[SubCategory]Product{
 		0x01: Product{ProductKey: 0x00000F, Description: "Weiland Doors’ Secondary Central Drive"},
 		0x02: Product{ProductKey: 0x000010, Description: "Weiland Doors’ Assist Drive"},
 		0x03: Product{ProductKey: 0x000011, Description: "Weiland Doors’ Elevation Drive"},
+		0x06: Product{ProductKey: 0x000000, Description: "MorningLinc [2458A1]"},
 	},
 	CategorySecurityHealthSafety: {
 		0x00: Product{ProductKey: 0x000027, Description: "First Alert ONELink RF to Insteon Bridge"},
 		0x01: Product{ProductKey: 0x00004a, Description: "Motion Sensor [2420M]"},
 		0x02: Product{ProductKey: 0x000049, Description: "TriggerLinc - INSTEON Open / Close Sensor [2421]"},
+		0x08: Product{ProductKey: 0x000000, Description: "Leak Sensor [2852-222]"},
+		0x09: Product{ProductKey: 0x000000, Description: "Hidden Door Sensor [2845-222]"},
 	},
 }
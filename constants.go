@@ -83,6 +83,10 @@ const (
 	cmdControlStatus     byte = 0x19
 	cmdControlGetOpFlags byte = 0x1f
 	cmdControlSetOpFlags byte = 0x20
+	cmdControlSetMSB     byte = 0x28
+	cmdControlPoke       byte = 0x29
+	cmdControlPeek       byte = 0x2B
+	cmdControlExtSetGet  byte = 0x2E
 	cmdControlAllLink    byte = 0x2F
 	cmdControlBeep       byte = 0x30
 
@@ -120,7 +124,9 @@ type AllLinkRecordFlags byte
 const (
 	AllLinkRecordFlagsInUse     AllLinkRecordFlags = 0x80
 	AllLinkRecordFlagsContoller AllLinkRecordFlags = 0x40
-	AllLinkRecordFlagsLast      AllLinkRecordFlags = 0x2
+	// AllLinkRecordFlagsLast is set on every record that has ever been used. Despite its name, a device's database ends
+	// at the first record where it's clear, see HighWater.
+	AllLinkRecordFlagsLast AllLinkRecordFlags = 0x2
 )
 
 func (al AllLinkRecordFlags) InUse() bool {
@@ -135,6 +141,12 @@ func (al AllLinkRecordFlags) Last() bool {
 	return !(al&0x2 == 0)
 }
 
+// HighWater returns true if the record marks the end of a device's database, neither it nor anything after it has
+// ever been used.
+func (al AllLinkRecordFlags) HighWater() bool {
+	return al&AllLinkRecordFlagsLast == 0
+}
+
 func (al AllLinkRecordFlags) String() string {
 	return fmt.Sprintf("InUse=%t, Controller=%t, Last=%t", al.InUse(), al.Controller(), al.Last())
 }
@@ -0,0 +1,130 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// These are the extended configuration settings of a window covering controller and the offsets of their current
+// values in the extended configuration data.
+const (
+	coverConfigFlags      byte = 0x05
+	coverConfigTravelTime byte = 0x06
+
+	coverDataFlags      = 5
+	coverDataTravelTime = 6
+)
+
+// coverFlagReversed is set in the configuration flags when the motor direction is reversed.
+const coverFlagReversed byte = 0x01
+
+// CoverConfig is the current configuration of a window covering controller.
+type CoverConfig struct {
+	// TravelTime is how long the motor runs to go from fully closed to fully open.
+	TravelTime time.Duration
+	// Reversed indicates the motor direction is reversed, so open and close are swapped.
+	Reversed bool
+}
+
+func (c *CoverConfig) String() string {
+	return fmt.Sprintf("TravelTime=%s, Reversed=%t", c.TravelTime, c.Reversed)
+}
+
+// Cover represents a window covering controller such as the micro open/close module [2444A2] driving a shade or
+// blind. On opens the covering, off closes it and the on-level is the position.
+type Cover struct {
+	*Device
+}
+
+// NewCover creates a new window covering controller by raw address.
+func NewCover(hub Hub, addr Address) (*Cover, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cover{Device: dev}, nil
+}
+
+// Open fully opens the covering.
+func (c *Cover) Open(ctx context.Context) error {
+	_, err := c.sendMessage(ctx, cmdControlOn, 0xFF)
+
+	return err
+}
+
+// Close fully closes the covering.
+func (c *Cover) Close(ctx context.Context) error {
+	_, err := c.sendMessage(ctx, cmdControlOff, 0)
+
+	return err
+}
+
+// Stop stops the covering wherever it currently is.
+func (c *Cover) Stop(ctx context.Context) error {
+	_, err := c.sendMessage(ctx, cmdControlStopDim, 0)
+
+	return err
+}
+
+// SetPosition moves the covering to a position between 0 (closed) and 100 (open) percent.
+func (c *Cover) SetPosition(ctx context.Context, percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("cover position out of range: %d", percent)
+	}
+
+	_, err := c.sendMessage(ctx, cmdControlOn, byte((percent*0xFF+50)/100))
+
+	return err
+}
+
+// GetPosition gets the current position of the covering between 0 (closed) and 100 (open) percent.
+func (c *Cover) GetPosition(ctx context.Context) (int, error) {
+	status, err := c.GetStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return (int(status.Level)*100 + 0xFF/2) / 0xFF, nil
+}
+
+// GetConfig reads the travel time and motor direction from the controller.
+func (c *Cover) GetConfig(ctx context.Context) (*CoverConfig, error) {
+	data, err := c.GetExtendedConfig(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CoverConfig{
+		TravelTime: time.Duration(data[coverDataTravelTime]) * time.Second,
+		Reversed:   data[coverDataFlags]&coverFlagReversed > 0,
+	}, nil
+}
+
+// SetTravelTime sets how long the motor runs to go from fully closed to fully open, between 1 and 255 seconds.
+func (c *Cover) SetTravelTime(ctx context.Context, travel time.Duration) error {
+	const maxSeconds = 0xFF
+
+	seconds := travel / time.Second
+	if seconds < 1 || seconds > maxSeconds {
+		return fmt.Errorf("cover travel time out of range: %s", travel)
+	}
+
+	return c.SetExtendedConfig(ctx, 0, coverConfigTravelTime, byte(seconds))
+}
+
+// SetReversed sets whether the motor direction is reversed.
+func (c *Cover) SetReversed(ctx context.Context, reversed bool) error {
+	data, err := c.GetExtendedConfig(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	flags := data[coverDataFlags] &^ coverFlagReversed
+	if reversed {
+		flags |= coverFlagReversed
+	}
+
+	return c.SetExtendedConfig(ctx, 0, coverConfigFlags, flags)
+}
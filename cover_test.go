@@ -0,0 +1,123 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type CoverTestSuite struct {
+	suite.Suite
+	mock  *InsteonHubMock
+	cover *insteon.Cover
+}
+
+func (s *CoverTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.cover, err = insteon.NewCover(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *CoverTestSuite) TestPosition() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x11, 0x80},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x11, 0x80, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x11, 0x80,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x00, 0x80,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x18, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x18, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x18, 0x00,
+		},
+	)
+
+	s.Require().NoError(s.cover.SetPosition(s.mock.ctx, 50))
+
+	pos, err := s.cover.GetPosition(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(50, pos)
+
+	s.Require().NoError(s.cover.Stop(s.mock.ctx))
+	s.Require().Error(s.cover.SetPosition(s.mock.ctx, 101))
+}
+
+func (s *CoverTestSuite) TestOpenClose() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x11, 0xFF},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x11, 0xFF, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x11, 0xFF,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x13, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x13, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x13, 0x00,
+		},
+	)
+
+	s.Require().NoError(s.cover.Open(s.mock.ctx))
+	s.Require().NoError(s.cover.Close(s.mock.ctx))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+// expectConfig expects the controller's extended configuration to be read, the flags are reported in D6 and the travel
+// time in D7.
+func (s *CoverTestSuite) expectConfig(flags, travel byte) {
+	s.mock.ExpectExtended(insteon.Address{0xAA, 0xBB, 0xCC}, 0x2E, 0x00, nil, []byte{
+		0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2E, 0x00,
+		0x00, 0x01, 0x00, 0x00, 0x00, flags, travel, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	})
+}
+
+func (s *CoverTestSuite) TestGetConfig() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.expectConfig(0x03, 0x1E)
+
+	cfg, err := s.cover.GetConfig(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(&insteon.CoverConfig{TravelTime: 30 * time.Second, Reversed: true}, cfg)
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *CoverTestSuite) TestSetTravelTime() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectExtended(insteon.Address{0xAA, 0xBB, 0xCC}, 0x2E, 0x00, []byte{0x00, 0x06, 0x1E})
+
+	s.Require().NoError(s.cover.SetTravelTime(s.mock.ctx, 30*time.Second))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+
+	s.Require().Error(s.cover.SetTravelTime(s.mock.ctx, 500*time.Millisecond))
+	s.Require().Error(s.cover.SetTravelTime(s.mock.ctx, 256*time.Second))
+}
+
+func (s *CoverTestSuite) TestSetReversed() {
+	// The other flags are left alone.
+	s.mock.ExpectEngineVersion(0x01)
+	s.expectConfig(0x02, 0x1E)
+	s.mock.ExpectExtended(insteon.Address{0xAA, 0xBB, 0xCC}, 0x2E, 0x00, []byte{0x00, 0x05, 0x03})
+	s.expectConfig(0x03, 0x1E)
+	s.mock.ExpectExtended(insteon.Address{0xAA, 0xBB, 0xCC}, 0x2E, 0x00, []byte{0x00, 0x05, 0x02})
+
+	s.Require().NoError(s.cover.SetReversed(s.mock.ctx, true))
+	s.Require().NoError(s.cover.SetReversed(s.mock.ctx, false))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func TestCoverSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &CoverTestSuite{})
+}
@@ -14,3 +14,15 @@ func TestCalculateCRC(t *testing.T) {
 		t.Fatalf("crc checksum fail: %x != %x", expected, actual)
 	}
 }
+
+func TestCalculateCRC16(t *testing.T) {
+	// A thermostat status request, cmd1, cmd2 and the first 12 data bytes.
+	test1 := []byte{0x2e, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	expected := uint16(0x9296)
+	actual := calculateCRC16(test1)
+
+	if expected != actual {
+		t.Fatalf("crc16 checksum fail: %x != %x", expected, actual)
+	}
+}
@@ -0,0 +1,197 @@
+package insteon
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDBIncomplete indicates records of a device's database were still missing after retrying.
+var ErrDBIncomplete = errors.New("unable to read the whole database")
+
+// These describe the layout of a device's All-Link database. Records are 8 bytes long and are numbered by the address
+// of their last byte, starting at the top of memory and growing down.
+const (
+	dbFirstRecord uint16 = 0x0FFF
+	dbRecordSize  uint16 = 8
+)
+
+// These are the values of the second data byte of an extended All-Link database command.
+const (
+	dbActionRead   byte = 0x00
+	dbActionRecord byte = 0x01
+	dbActionWrite  byte = 0x02
+)
+
+const (
+	// dbRecordTimeout is how long to wait for the next record before asking for the missing ones again.
+	dbRecordTimeout = 3 * time.Second
+	// dbRetries is how many times a missing record is asked for before giving up.
+	dbRetries = 3
+)
+
+// DatabaseProgress is called with the number of records read so far while a device's database is read.
+type DatabaseProgress func(records int)
+
+// GetDatabase reads the device's All-Link database, see ReadDatabase.
+func (d *Device) GetDatabase(ctx context.Context) (map[uint16]*AllLinkRecord, error) {
+	return d.ReadDatabase(ctx, nil)
+}
+
+// ReadDatabase reads the device's All-Link database, keyed by the memory address of each record. Records that have
+// been deleted are included so the free space can be reused, the high water record that ends the database isn't.
+// Reading a large database takes a while, so progress, if not nil, is called as each record arrives.
+//
+// Records are read with the extended All-Link database command. Any records lost along the way are asked for again
+// one at a time. Devices that predate extended messages are read a byte at a time from memory instead.
+func (d *Device) ReadDatabase(ctx context.Context, progress DatabaseProgress) (map[uint16]*AllLinkRecord, error) {
+	version, err := d.GetEngineVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if progress == nil {
+		progress = func(int) {}
+	}
+
+	if version == EngineVersionI1 {
+		return d.readDatabaseMemory(ctx, progress)
+	}
+
+	return d.readDatabaseExtended(ctx, progress)
+}
+
+// dbRead collects the records of a device's database as they arrive, in whatever order.
+type dbRead struct {
+	records map[uint16]*AllLinkRecord
+	end     uint16
+}
+
+// missing returns the address of the first record that hasn't arrived yet, or false if the whole database has.
+func (r *dbRead) missing() (uint16, bool) {
+	for memAddr := dbFirstRecord; r.end == 0 || memAddr > r.end; memAddr -= dbRecordSize {
+		if _, ok := r.records[memAddr]; !ok {
+			return memAddr, true
+		}
+	}
+
+	return 0, false
+}
+
+func (d *Device) readDatabaseExtended(ctx context.Context, progress DatabaseProgress) (map[uint16]*AllLinkRecord,
+	error) {
+	read := &dbRead{records: make(map[uint16]*AllLinkRecord)}
+	attempts := make(map[uint16]int)
+
+	// Ask for everything at once, then for whatever got lost one record at a time.
+	memAddr, count := uint16(0), byte(0)
+
+	for {
+		if err := d.requestRecords(ctx, memAddr, count); err != nil {
+			return nil, err
+		}
+
+		if err := d.receiveRecords(ctx, read, count, progress); err != nil {
+			return nil, err
+		}
+
+		var ok bool
+		if memAddr, ok = read.missing(); !ok {
+			return read.records, nil
+		}
+
+		if attempts[memAddr]++; attempts[memAddr] > dbRetries {
+			return nil, errors.Wrapf(ErrDBIncomplete, "address: %s, record: %04x", d.address, memAddr)
+		}
+
+		count = 1
+	}
+}
+
+// requestRecords asks the device for count records starting at memAddr. A count of zero asks for the whole database.
+func (d *Device) requestRecords(ctx context.Context, memAddr uint16, count byte) error {
+	_, err := d.sendExtended(ctx, cmdControlAllLink, 0,
+		[14]byte{0, dbActionRead, byte(memAddr >> 8), byte(memAddr), count})
+
+	return err
+}
+
+// receiveRecords collects the count records asked for, or with a count of zero the rest of the database, stopping
+// early if the device goes quiet. Records that arrive corrupted are dropped and asked for again later.
+func (d *Device) receiveRecords(ctx context.Context, read *dbRead, count byte, progress DatabaseProgress) error {
+	for received := byte(0); count == 0 || received < count; {
+		recCtx, cancel := context.WithTimeout(ctx, dbRecordTimeout)
+		rsp, err := d.expectExtended(recCtx, cmdControlAllLink)
+
+		cancel()
+
+		switch {
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case errors.Cause(err) == ErrChecksum:
+			continue
+		case err != nil:
+			// The device has gone quiet.
+			return nil
+		case rsp.data[1] != dbActionRecord:
+			continue
+		}
+
+		received++
+
+		memAddr := uint16(rsp.data[2])<<8 + uint16(rsp.data[3])
+		rec := &AllLinkRecord{}
+		rec.fromBytes(rsp.data[3:])
+
+		if rec.Flags.HighWater() {
+			// Nothing follows the high water record.
+			read.end = memAddr
+
+			return nil
+		}
+
+		if _, ok := read.records[memAddr]; !ok {
+			read.records[memAddr] = rec
+			progress(len(read.records))
+		}
+	}
+
+	return nil
+}
+
+// readDatabaseMemory reads the database straight out of the device's memory, one record at a time.
+func (d *Device) readDatabaseMemory(ctx context.Context, progress DatabaseProgress) (map[uint16]*AllLinkRecord,
+	error) {
+	d.memMu.Lock()
+	defer d.memMu.Unlock()
+
+	db := make(map[uint16]*AllLinkRecord)
+
+	for memAddr := dbFirstRecord; memAddr >= dbRecordSize; memAddr -= dbRecordSize {
+		// The flags come first, there's no need to read the rest of the high water record.
+		start := memAddr - dbRecordSize + 1
+		buf := make([]byte, 2, 2+dbRecordSize)
+
+		for offset := uint16(0); offset < dbRecordSize; offset++ {
+			value, err := d.peek(ctx, start+offset)
+			if err != nil {
+				return nil, err
+			}
+
+			buf = append(buf, value)
+
+			if offset == 0 && AllLinkRecordFlags(value).HighWater() {
+				return db, nil
+			}
+		}
+
+		rec := &AllLinkRecord{}
+		rec.fromBytes(buf)
+
+		db[memAddr] = rec
+		progress(len(db))
+	}
+
+	return db, nil
+}
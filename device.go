@@ -1,11 +1,11 @@
 package insteon
 
 import (
-	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
-	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -14,6 +14,10 @@ import (
 var (
 	ErrDBEntryNotFound      = errors.New("unable to find database entry")
 	ErrDBEntryAlreadyExists = errors.New("database entry for this device already exists")
+	// ErrNAK indicates the device received the command but refused to process it.
+	ErrNAK = errors.New("device sent a negative acknowledgement")
+	// ErrAddressFormat indicates text that isn't an Insteon address.
+	ErrAddressFormat = errors.New("invalid address")
 )
 
 type Address [3]byte
@@ -22,10 +26,38 @@ func (a Address) String() string {
 	return fmt.Sprintf("%02X:%02X:%02X", a[0], a[1], a[2])
 }
 
+// MarshalText formats the address the same way as String.
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText parses an address of three hex bytes, either run together or separated by colons or dots.
+func (a *Address) UnmarshalText(text []byte) error {
+	buf, err := hex.DecodeString(strings.NewReplacer(":", "", ".", "").Replace(string(text)))
+	if err != nil || len(buf) != len(a) {
+		return errors.Wrapf(ErrAddressFormat, "%q", text)
+	}
+
+	copy(a[:], buf)
+
+	return nil
+}
+
 // Device represents an Insteon device.
 type Device struct {
+	// IdentifyTimeout is how long Identify waits for the device to answer, DefaultIdentifyTimeout if it's zero.
+	IdentifyTimeout time.Duration
+
 	address Address
 	hub     Hub
+
+	// cacheMu guards the details that are learned from the device once and then cached.
+	cacheMu sync.Mutex
+	engine  *EngineVersion
+
+	// memMu serializes access to the device's memory, which goes through the MSB last set on the device.
+	memMu sync.Mutex
+	msb   *byte
 }
 
 // NewDevice creates a new device by raw address.
@@ -80,18 +112,113 @@ func (d *Device) TurnOffRamp(ctx context.Context, ramp bool) error {
 	return err
 }
 
+// SetFanLevel sets the raw fan level of a FanLinc.
+//
+// Deprecated: use FanLinc.SetFanSpeed instead.
 func (d *Device) SetFanLevel(ctx context.Context, level byte) error {
-	_, err := d.hub.SendExtendedMessage(ctx, d.address, cmdControlOn, level, [14]byte{2})
+	_, err := d.sendExtended(ctx, cmdControlOn, level, [14]byte{2})
 
 	return err
 }
 
+// These are the cmd1 values of the broadcast a device sends in reply to an ID request, as if its SET button had been
+// pressed.
+const (
+	cmdBroadcastSetButtonResponder  byte = 0x01
+	cmdBroadcastSetButtonController byte = 0x02
+)
+
+// DefaultIdentifyTimeout is how long Identify waits for the device to answer before asking for its product data
+// instead, unless the device's IdentifyTimeout says otherwise.
+const DefaultIdentifyTimeout = 5 * time.Second
+
+// anyExpecter is implemented by hubs that can wait for the first of several kinds of events, which lets Identify take
+// whichever reply the device sends. Other hubs only get to see the SET button broadcast.
+type anyExpecter interface {
+	expectAny(ctx context.Context, evts ...Event) (Event, error)
+}
+
 type DeviceIdentification struct {
 	Category    byte
 	SubCategory byte
 	Firmware    byte
 }
 
+// Description returns the product description of the identified device, or an empty string if it's unknown.
+func (id *DeviceIdentification) Description() string {
+	return GetProductDesc(Category(id.Category), SubCategory(id.SubCategory))
+}
+
+func (id *DeviceIdentification) String() string {
+	return fmt.Sprintf("Category=%02X, SubCategory=%02X, Firmware=%02X", id.Category, id.SubCategory, id.Firmware)
+}
+
+// Identify asks the device to identify itself. Most devices reply with the same broadcast they send when their SET
+// button is pressed, which carries the category, subcategory and firmware version. Some devices reply with their
+// product data instead, and devices that don't reply at all are asked for it directly. Product data has no firmware
+// version.
+func (d *Device) Identify(ctx context.Context) (*DeviceIdentification, error) {
+	if _, err := d.sendMessage(ctx, cmdControlID, 0); err != nil {
+		return nil, err
+	}
+
+	timeout := d.IdentifyTimeout
+	if timeout <= 0 {
+		timeout = DefaultIdentifyTimeout
+	}
+
+	idCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	expect := func(ctx context.Context) (Event, error) {
+		return d.hub.Expect(ctx, &StdCommandResponse{})
+	}
+
+	if hub, ok := d.hub.(anyExpecter); ok {
+		expect = func(ctx context.Context) (Event, error) {
+			return hub.expectAny(ctx, &StdCommandResponse{}, &ExtCommandResponse{})
+		}
+	}
+
+	for {
+		evt, err := expect(idCtx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			break
+		}
+
+		switch rsp := evt.(type) {
+		case *StdCommandResponse:
+			if rsp.From() != d.address || !rsp.Flags().BroadcastNAK() || rsp.Flags().AllLink() {
+				continue
+			}
+
+			if rsp.Cmd1() == cmdBroadcastSetButtonResponder || rsp.Cmd1() == cmdBroadcastSetButtonController {
+				// The device puts its identity where the destination address would normally be.
+				return &DeviceIdentification{Category: rsp.to[0], SubCategory: rsp.to[1], Firmware: rsp.to[2]}, nil
+			}
+		case *ExtCommandResponse:
+			if rsp.From() == d.address && rsp.Cmd1() == cmdControlProduct {
+				return productIdentification(productFromData(rsp.Data())), nil
+			}
+		}
+	}
+
+	prd, err := d.GetProductData(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return productIdentification(prd), nil
+}
+
+func productIdentification(prd *Product) *DeviceIdentification {
+	return &DeviceIdentification{Category: byte(prd.Category), SubCategory: byte(prd.SubCategory)}
+}
+
 func (d *Device) Ping(ctx context.Context) error {
 	_, err := d.hub.SendMessage(ctx, d.address, cmdControlPing, 0)
 	if err != nil {
@@ -102,24 +229,27 @@ func (d *Device) Ping(ctx context.Context) error {
 }
 
 func (d *Device) GetProductData(ctx context.Context) (*Product, error) {
-	_, err := d.hub.SendMessage(ctx, d.address, cmdControlProduct, 0)
-	if err != nil {
+	if _, err := d.sendMessage(ctx, cmdControlProduct, 0); err != nil {
 		return nil, err
 	}
 
-	evt, err := d.hub.Expect(ctx, &ExtCommandResponse{})
+	rsp, err := d.expectExtended(ctx, cmdControlProduct)
 	if err != nil {
 		return nil, err
 	}
 
-	data := evt.(*ExtCommandResponse).Data()
+	return productFromData(rsp.Data()), nil
+}
+
+// productFromData parses the data of a product data response.
+func productFromData(data []byte) *Product {
 	prd := &Product{}
 	prd.ProductKey = uint(data[1])<<16 + uint(data[2])<<8 + uint(data[3])
 	prd.Category = Category(data[4])
 	prd.SubCategory = SubCategory(data[5])
 	prd.Description = GetProductDesc(prd.Category, prd.SubCategory)
 
-	return prd, nil
+	return prd
 }
 
 func (d *Device) GetName(ctx context.Context) (string, error) {
@@ -150,7 +280,7 @@ func (d *Device) SetName(ctx context.Context, name string) error {
 		data[idx] = name[idx]
 	}
 
-	_, err := d.hub.SendExtendedMessage(ctx, d.address, cmdControlProduct, 2, data)
+	_, err := d.sendExtended(ctx, cmdControlProduct, 2, data)
 	if err != nil {
 		return err
 	}
@@ -158,34 +288,6 @@ func (d *Device) SetName(ctx context.Context, name string) error {
 	return nil
 }
 
-func (d *Device) GetDatabase(ctx context.Context) (map[uint16]*AllLinkRecord, error) {
-	data := [14]byte{}
-
-	if _, err := d.hub.SendExtendedMessage(ctx, d.address, cmdControlAllLink, 0, data); err != nil {
-		return nil, err
-	}
-
-	db := make(map[uint16]*AllLinkRecord)
-
-	for {
-		rsp, err := d.hub.Expect(ctx, &ExtCommandResponse{})
-		if err != nil {
-			return nil, err
-		}
-
-		evt := rsp.(*ExtCommandResponse)
-		addr := uint16(evt.data[2])<<8 + uint16(evt.data[3])
-		dbEntry := &AllLinkRecord{}
-		dbEntry.fromBytes(evt.data[3:])
-
-		if dbEntry.Flags.Last() {
-			return db, nil
-		}
-
-		db[addr] = dbEntry
-	}
-}
-
 // GetStatus gets the current power status of the device.
 func (d *Device) GetStatus(ctx context.Context) (*DeviceStatus, error) {
 	return d.GetStatusChannel(ctx, 0)
@@ -200,166 +302,60 @@ func (d *Device) StartAllLink(ctx context.Context, group byte) error {
 	return nil
 }
 
+// DeleteAllLink deletes the entry for addr and group from the device's All-Link database, see LinkDatabase.
 func (d *Device) DeleteAllLink(ctx context.Context, addr Address, group byte, controller bool) error {
-	db, err := d.GetDatabase(ctx)
-	if err != nil {
-		return err
-	}
-
-	memAddr, lastAddr := findAllLinkDBEntry(db, addr, group, controller)
-	if memAddr == 0 {
-		return errors.Wrapf(ErrDBEntryNotFound, "address: %s, group: %d, controller: %t", addr, group, controller)
-	}
-
-	secondLastAddr := uint16(0)
-
-	for a := range db {
-		if a > memAddr && a < lastAddr {
-			secondLastAddr = a
-		}
-	}
-
-	log.Printf("memAddr=%x, lastAddr=%x, secondLast=%x", memAddr, lastAddr, secondLastAddr)
-
-	// If this isn't the last entry in the list.
-	if memAddr != lastAddr {
-		// We need to swap the entry we want to delete with the current last entry.
-		keepEntry := db[lastAddr]
-
-		keepFlags := keepEntry.Flags
-		if secondLastAddr == memAddr {
-			keepFlags |= AllLinkRecordFlagsLast
-		} else {
-			keepFlags &= 0xFD
-		}
-
-		log.Printf("performing swap: %x <-> %x", memAddr, lastAddr)
-
-		if _, err = d.hub.SendExtendedMessage(ctx, d.address, cmdControlAllLink, 0,
-			d.modifyDbCommand(memAddr, keepFlags, keepEntry.Group, keepEntry.Address, keepEntry.Data)); err != nil {
-			return err
-		}
-
-		time.Sleep(500 * time.Millisecond)
-	}
-
-	// Mark the last entry as empty.
-	log.Printf("marking empty: %x", lastAddr)
-
-	if _, err = d.hub.SendExtendedMessage(ctx, d.address, cmdControlAllLink, 0,
-		d.modifyDbCommand(lastAddr, 0, 0, [3]byte{}, [3]byte{})); err != nil {
-		return err
-	}
-
-	// If we haven't already done this,
-	if secondLastAddr != memAddr && secondLastAddr > 0 {
-		time.Sleep(500 * time.Millisecond)
-
-		// We need to re-write the new last entry to toggle the last flag.
-		newLast := db[secondLastAddr]
-		log.Printf("marking last: %x", secondLastAddr)
-
-		if _, err = d.hub.SendExtendedMessage(ctx, d.address, cmdControlAllLink, 0,
-			d.modifyDbCommand(secondLastAddr, newLast.Flags|AllLinkRecordFlagsLast,
-				newLast.Group, newLast.Address, newLast.Data)); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return d.changeAllLink(ctx, func(db *LinkDatabase) error {
+		return db.Delete(addr, group, controller)
+	})
 }
 
+// UpdateAllLink changes the data of the entry for addr and group in the device's All-Link database, see LinkDatabase.
 func (d *Device) UpdateAllLink(ctx context.Context, addr Address, group byte, data [3]byte, controller bool) error {
-	db, err := d.GetDatabase(ctx)
-	if err != nil {
-		return err
-	}
-
-	memAddr, lastAddr := findAllLinkDBEntry(db, addr, group, controller)
-	if memAddr == 0 {
-		return errors.Wrapf(ErrDBEntryNotFound, "address: %s, group: %d, controller: %t", addr, group, controller)
-	}
-
-	flags := AllLinkRecordFlagsInUse
-	if memAddr == lastAddr {
-		flags |= AllLinkRecordFlagsLast
-	}
-
-	if controller {
-		flags |= AllLinkRecordFlagsContoller
-	}
-
-	_, err = d.hub.SendExtendedMessage(ctx, d.address, cmdControlAllLink, 0,
-		d.modifyDbCommand(memAddr, flags, group, addr, data))
-
-	return err
+	return d.changeAllLink(ctx, func(db *LinkDatabase) error {
+		return db.Update(addr, group, controller, data)
+	})
 }
 
+// AddAllLink adds an entry for addr and group to the device's All-Link database, see LinkDatabase.
 func (d *Device) AddAllLink(ctx context.Context, addr Address, group byte, data [3]byte, controller bool) error {
-	db, err := d.GetDatabase(ctx)
+	return d.changeAllLink(ctx, func(db *LinkDatabase) error {
+		return db.Add(addr, group, controller, data)
+	})
+}
+
+// changeAllLink reads the device's All-Link database, makes a change and applies it.
+func (d *Device) changeAllLink(ctx context.Context, change func(db *LinkDatabase) error) error {
+	db, err := d.GetLinkDatabase(ctx)
 	if err != nil {
 		return err
 	}
 
-	memAddr, lastAddr := findAllLinkDBEntry(db, addr, group, controller)
-	if memAddr != 0 {
-		return errors.Wrapf(ErrDBEntryAlreadyExists, "address: %s, group: %d, controller: %t", addr, group, controller)
-	}
-
-	// We need to do two things here, we need to add our new entry and then update the previous last entry to make sure
-	// the last entry flag is cleared.
-	memAddr = lastAddr + 0x8
-
-	flags := AllLinkRecordFlagsInUse | AllLinkRecordFlagsLast
-	if controller {
-		flags |= AllLinkRecordFlagsContoller
-	}
-
-	// Create new last entry.
-	if _, err = d.hub.SendExtendedMessage(ctx, d.address, cmdControlAllLink, 0,
-		d.modifyDbCommand(memAddr, flags, group, addr, data)); err != nil {
+	if err := change(db); err != nil {
 		return err
 	}
 
-	oldLast := db[lastAddr]
-	if oldLast.Flags&AllLinkRecordFlagsLast > 0 {
-		// We need to clear the last flag from the previous last entry.
-		if _, err = d.hub.SendExtendedMessage(ctx, d.address, cmdControlAllLink, 0,
-			d.modifyDbCommand(lastAddr, oldLast.Flags&0xFD, oldLast.Group, oldLast.Address, oldLast.Data)); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return db.Apply(ctx)
 }
 
-func findAllLinkDBEntry(db map[uint16]*AllLinkRecord, addr Address, group byte, controller bool) (uint16, uint16) {
-	var rByte AllLinkRecordFlags
-	if controller {
-		rByte = AllLinkRecordFlagsContoller
-	}
-
-	foundAddr := uint16(0)
-
-	lastAddr := uint16(0xFFFF)
+// findAllLinkDBEntry returns the memory address of the first entry in use for addr and group, or 0 if there isn't
+// one.
+func findAllLinkDBEntry(db map[uint16]*AllLinkRecord, addr Address, group byte, controller bool) uint16 {
+	found := uint16(0)
 
-	for memAddr, d := range db {
-		if bytes.Equal(d.Address[:], addr[:]) && d.Flags&AllLinkRecordFlagsContoller == rByte && d.Group == group {
-			foundAddr = memAddr
-		}
-
-		if memAddr < lastAddr {
-			lastAddr = memAddr
+	for memAddr, rec := range db {
+		if rec.Flags.InUse() && rec.Address == addr && rec.Flags.Controller() == controller && rec.Group == group &&
+			memAddr > found {
+			found = memAddr
 		}
 	}
 
-	return foundAddr, lastAddr
+	return found
 }
 
 func (d *Device) modifyDbCommand(memAddr uint16, flags AllLinkRecordFlags, group byte, addr Address, data [3]byte) [14]byte {
 	cmd := [14]byte{
 		0,                      // Unused
-		0x2,                    // Modify
+		dbActionWrite,          // Modify
 		byte(memAddr>>8) & 0xF, // Address High Nibble
 		byte(memAddr),          // Address Low Byte
 		0,                      // Unused
@@ -371,11 +367,9 @@ func (d *Device) modifyDbCommand(memAddr uint16, flags AllLinkRecordFlags, group
 		data[0],                // Data
 		data[1],                //
 		data[2],                //
-		0,                      // CRC
+		0,                      // Checksum, filled in by sendExtended
 	}
 
-	cmd[13] = calculateCRC(append([]byte{cmdControlAllLink, 0}, cmd[:]...))
-
 	return cmd
 }
 
@@ -426,7 +420,7 @@ func (d *Device) GetOperatingFlags(ctx context.Context) (DeviceOpFlags, error) {
 
 // GetStatusChannel gets the current power status of the device.
 func (d *Device) GetStatusChannel(ctx context.Context, channel byte) (*DeviceStatus, error) {
-	rsp, err := d.hub.SendMessage(ctx, d.address, cmdQueryStatusRequest, channel)
+	rsp, err := d.sendMessage(ctx, cmdQueryStatusRequest, channel)
 	if err != nil {
 		return nil, err
 	}
@@ -453,3 +447,122 @@ type DeviceStatus struct {
 	// Current power level of the device.
 	Level byte
 }
+
+// GetExtendedConfig requests the extended configuration for a group (or button) on the device and returns the data
+// bytes of the device's reply. The layout of the data is device specific.
+func (d *Device) GetExtendedConfig(ctx context.Context, group byte) ([14]byte, error) {
+	if _, err := d.sendExtended(ctx, cmdControlExtSetGet, 0, [14]byte{group}); err != nil {
+		return [14]byte{}, err
+	}
+
+	for {
+		rsp, err := d.expectExtended(ctx, cmdControlExtSetGet)
+		if err != nil {
+			return [14]byte{}, err
+		}
+
+		// The second data byte distinguishes the reply (0x01) from the request (0x00).
+		if rsp.data[1] == 0x01 {
+			return rsp.data, nil
+		}
+	}
+}
+
+// SetExtendedConfig changes a single extended configuration setting for a group (or button) on the device. The
+// meaning of setting and its values are device specific.
+func (d *Device) SetExtendedConfig(ctx context.Context, group byte, setting byte, values ...byte) error {
+	version, err := d.GetEngineVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The values end where the checksum starts.
+	space := 11
+	if usesCRC16(version, cmdControlExtSetGet) {
+		space = 10
+	}
+
+	if len(values) > space {
+		return fmt.Errorf("too many values for extended configuration setting %x: %d", setting, len(values))
+	}
+
+	data := [14]byte{group, setting}
+	copy(data[2:], values)
+
+	_, err = d.sendExtended(ctx, cmdControlExtSetGet, 0, data)
+
+	return err
+}
+
+// setOperatingFlag changes one of the device's operating flags. Each flag has separate values to turn it on and off.
+func (d *Device) setOperatingFlag(ctx context.Context, flag byte) error {
+	_, err := d.sendMessage(ctx, cmdControlSetOpFlags, flag)
+
+	return err
+}
+
+// sendMessage sends a standard message to the device and waits for the device's own acknowledgement, skipping over
+// unrelated traffic from other devices that arrives in the meantime.
+func (d *Device) sendMessage(ctx context.Context, cmd1, cmd2 byte) (CommandResponse, error) {
+	rsp, err := d.hub.SendMessage(ctx, d.address, cmd1, cmd2)
+
+	return d.awaitAck(ctx, cmd1, rsp, err)
+}
+
+// sendExtended sends an extended message to the device and waits for the device's own acknowledgement, the same way
+// sendMessage does for standard messages. The checksum the device expects is filled in automatically, overwriting the
+// last data byte, or the last two for a CRC.
+func (d *Device) sendExtended(ctx context.Context, cmd1, cmd2 byte, data [14]byte) (CommandResponse, error) {
+	version, err := d.GetEngineVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if usesCRC16(version, cmd1) {
+		crc := calculateCRC16(append([]byte{cmd1, cmd2}, data[:12]...))
+		data[12], data[13] = byte(crc>>8), byte(crc)
+	} else {
+		data[13] = calculateCRC(append([]byte{cmd1, cmd2}, data[:13]...))
+	}
+
+	rsp, err := d.hub.SendExtendedMessage(ctx, d.address, cmd1, cmd2, data)
+
+	return d.awaitAck(ctx, cmd1, rsp, err)
+}
+
+// awaitAck waits for the device's own acknowledgement of a command, starting with the first response the hub passed
+// back from sending it.
+func (d *Device) awaitAck(ctx context.Context, cmd1 byte, rsp CommandResponse, err error) (CommandResponse, error) {
+	for err == nil && (rsp.From() != d.address || !rsp.Flags().Acknowledgement()) {
+		var evt Event
+
+		if evt, err = d.hub.Expect(ctx, &StdCommandResponse{}); err == nil {
+			rsp = evt.(*StdCommandResponse)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp.Flags().BroadcastNAK() {
+		return nil, errors.Wrapf(ErrNAK, "address: %s, cmd1: %x, reason: %x", d.address, cmd1, rsp.Cmd2())
+	}
+
+	return rsp, nil
+}
+
+// expectExtended waits for an extended message from the device with the given command and verifies its checksum.
+func (d *Device) expectExtended(ctx context.Context, cmd1 byte) (*ExtCommandResponse, error) {
+	for {
+		evt, err := d.hub.Expect(ctx, &ExtCommandResponse{})
+		if err != nil {
+			return nil, err
+		}
+
+		rsp := evt.(*ExtCommandResponse)
+		if rsp.From() == d.address && rsp.Cmd1() == cmd1 {
+			return rsp, d.checkChecksum(rsp)
+		}
+	}
+}
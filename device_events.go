@@ -0,0 +1,78 @@
+package insteon
+
+import "sync"
+
+// DeviceEvent is an event decoded from the messages a particular device sends to the modem, such as a fan changing
+// speed or a sensor being tripped.
+type DeviceEvent interface {
+	// From is the address of the device that generated the event.
+	From() Address
+}
+
+// DeviceEventListener is notified of the DeviceEvents generated by the devices it's registered with.
+type DeviceEventListener func(DeviceEvent)
+
+// deviceEvent holds the fields common to all DeviceEvent implementations.
+type deviceEvent struct {
+	from Address
+}
+
+func (e deviceEvent) From() Address {
+	return e.from
+}
+
+// deviceEvents keeps track of the listeners registered with a typed device and hooks the device into the hub's event
+// stream the first time it's needed.
+type deviceEvents struct {
+	mu        sync.Mutex
+	once      sync.Once
+	listeners []DeviceEventListener
+}
+
+func (de *deviceEvents) add(listener DeviceEventListener) {
+	de.mu.Lock()
+	defer de.mu.Unlock()
+
+	de.listeners = append(de.listeners, listener)
+}
+
+func (de *deviceEvents) emit(evt DeviceEvent) {
+	de.mu.Lock()
+	listeners := make([]DeviceEventListener, len(de.listeners))
+	copy(listeners, de.listeners)
+	de.mu.Unlock()
+
+	for _, l := range listeners {
+		l(evt)
+	}
+}
+
+// watch registers handler with the hub so it receives every message sent by addr. The handler is only registered
+// once no matter how many times watch is called.
+func (de *deviceEvents) watch(hub Hub, addr Address, handler func(CommandResponse)) {
+	de.once.Do(func() {
+		hub.AddEventListener(func(evt Event, err error) {
+			if err != nil {
+				return
+			}
+
+			rsp, ok := evt.(CommandResponse)
+			if !ok || rsp.From() != addr {
+				return
+			}
+
+			handler(rsp)
+		})
+	})
+}
+
+// groupBroadcast returns the group an All-Link broadcast was sent to. Direct messages and All-Link cleanups aren't
+// considered broadcasts.
+func groupBroadcast(rsp CommandResponse) (byte, bool) {
+	flags := rsp.Flags()
+	if !flags.BroadcastNAK() || !flags.AllLink() {
+		return 0, false
+	}
+
+	return rsp.To()[2], true
+}
@@ -0,0 +1,268 @@
+package insteon_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type DeviceTestSuite struct {
+	suite.Suite
+	mock   *InsteonHubMock
+	device *insteon.Device
+}
+
+func (s *DeviceTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.device, err = insteon.NewDevice(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *DeviceTestSuite) TestIdentify() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x10, 0x00,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45, 0x8B, 0x01, 0x00,
+		},
+	)
+
+	id, err := s.device.Identify(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(&insteon.DeviceIdentification{Category: 0x01, SubCategory: 0x2E, Firmware: 0x45}, id)
+	s.Require().Equal("FanLinc [2475F]", id.Description())
+}
+
+func (s *DeviceTestSuite) TestIdentifyProductData() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x10, 0x00,
+			0x02, 0x51, 0x11, 0x22, 0x33, 0x01, 0x02, 0x03, 0x1B, 0x03, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x02, 0x39, 0, 0, 0, 0, 0, 0, 0, 0,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x03, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x01, 0x2E, 0, 0, 0, 0, 0, 0, 0, 0,
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(s.mock.ctx, time.Second)
+	defer cancel()
+
+	id, err := s.device.Identify(ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(&insteon.DeviceIdentification{Category: 0x01, SubCategory: 0x2E}, id)
+}
+
+func (s *DeviceTestSuite) TestIdentifyTimeout() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x10, 0x00,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x03, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x03, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x03, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x03, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x02, 0x39, 0, 0, 0, 0, 0, 0, 0, 0,
+		},
+	)
+
+	// The device never answers the ID request, so Identify falls back to asking for product data.
+	s.device.IdentifyTimeout = 100 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(s.mock.ctx, 2*time.Second)
+	defer cancel()
+
+	id, err := s.device.Identify(ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(&insteon.DeviceIdentification{Category: 0x02, SubCategory: 0x39}, id)
+	s.Require().Equal("On/Off Outlet [2663-222]", id.Description())
+}
+
+func (s *DeviceTestSuite) TestGetEngineVersion() {
+	s.mock.ExpectEngineVersion(0x01)
+
+	version, err := s.device.GetEngineVersion(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(insteon.EngineVersionI2, version)
+
+	// The version is cached, so the second call doesn't ask again.
+	version, err = s.device.GetEngineVersion(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(insteon.EngineVersionI2, version)
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *DeviceTestSuite) TestGetEngineVersionNAK() {
+	s.expectI2CS()
+
+	version, err := s.device.GetEngineVersion(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(insteon.EngineVersionI2CS, version)
+}
+
+func (s *DeviceTestSuite) TestChecksumI2CS() {
+	s.expectI2CS()
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x63, 0x6B},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x63, 0x6B, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2E, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2E, 0x00,
+			0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	)
+
+	_, err := s.device.GetExtendedConfig(s.mock.ctx, 0)
+	s.Require().ErrorIs(err, insteon.ErrChecksum)
+}
+
+func (s *DeviceTestSuite) TestSetExtendedConfigI2CS() {
+	s.expectI2CS()
+
+	// The last two data bytes hold the CRC, leaving room for 10 values.
+	err := s.device.SetExtendedConfig(s.mock.ctx, 0, 0x01, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)
+	s.Require().Error(err)
+}
+
+func (s *DeviceTestSuite) TestGetDatabase() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2F, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xCF},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2F, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xCF, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2F, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00,
+			0x00, 0x01, 0x0F, 0xFF, 0x00, 0xE2, 0x01, 0x01, 0x02, 0x03, 0x00, 0x00, 0x00, 0x00,
+			// Another device reading its own database at the same time.
+			0x02, 0x51, 0x11, 0x22, 0x33, 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00,
+			0x00, 0x01, 0x0F, 0xF7, 0x00, 0xA2, 0x01, 0x44, 0x55, 0x66, 0x00, 0x00, 0x00, 0x00,
+			// 0FF7 got lost.
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00,
+			0x00, 0x01, 0x0F, 0xEF, 0x00, 0x22, 0x01, 0x01, 0x02, 0x03, 0x00, 0x00, 0x00, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00,
+			0x00, 0x01, 0x0F, 0xE7, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+		// Only the missing record is asked for again.
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2F, 0x00, 0x00, 0x00, 0x0F, 0xF7, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0xC8},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2F, 0x00, 0x00, 0x00, 0x0F, 0xF7, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0xC8, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2F, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00,
+			0x00, 0x01, 0x0F, 0xF7, 0x00, 0xA2, 0x02, 0x01, 0x02, 0x03, 0xFF, 0x1F, 0x02, 0x00,
+		},
+	)
+
+	var progress []int
+
+	db, err := s.device.ReadDatabase(s.mock.ctx, func(records int) { progress = append(progress, records) })
+	s.Require().NoError(err)
+	s.Require().Equal(map[uint16]*insteon.AllLinkRecord{
+		0x0FFF: {Flags: 0xE2, Group: 0x01, Address: insteon.Address{0x01, 0x02, 0x03}},
+		0x0FF7: {Flags: 0xA2, Group: 0x02, Address: insteon.Address{0x01, 0x02, 0x03}, Data: [3]byte{0xFF, 0x1F, 0x02}},
+		0x0FEF: {Flags: 0x22, Group: 0x01, Address: insteon.Address{0x01, 0x02, 0x03}},
+	}, db)
+	s.Require().Equal([]int{1, 2, 3}, progress)
+}
+
+func (s *DeviceTestSuite) TestGetDatabaseMemory() {
+	s.mock.ExpectEngineVersion(0x00)
+
+	// An i1 device is read a byte at a time, stopping at the flags of the high water record.
+	s.expectSetMSB(0x0F)
+
+	for idx, value := range []byte{0xE2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1F, 0x01} {
+		s.expectPeek(0xF8+byte(idx), value)
+	}
+
+	s.expectPeek(0xF0, 0x00)
+
+	db, err := s.device.GetDatabase(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(map[uint16]*insteon.AllLinkRecord{
+		0x0FFF: {Flags: 0xE2, Group: 0x01, Address: insteon.Address{0x01, 0x02, 0x03}, Data: [3]byte{0xFF, 0x1F, 0x01}},
+	}, db)
+}
+
+func (s *DeviceTestSuite) TestPeekBlock() {
+	// The MSB is only set again when the block crosses into the next page.
+	s.expectSetMSB(0x01)
+	s.expectPeek(0xFE, 0xAA)
+	s.expectPeek(0xFF, 0xBB)
+	s.expectSetMSB(0x02)
+	s.expectPeek(0x00, 0xCC)
+	s.expectPeek(0x01, 0xDD)
+
+	buf, err := s.device.PeekBlock(s.mock.ctx, 0x01FE, 3)
+	s.Require().NoError(err)
+	s.Require().Equal([]byte{0xAA, 0xBB, 0xCC}, buf)
+
+	value, err := s.device.Peek(s.mock.ctx, 0x0201)
+	s.Require().NoError(err)
+	s.Require().Equal(byte(0xDD), value)
+}
+
+func (s *DeviceTestSuite) TestPoke() {
+	s.expectSetMSB(0x0F)
+	s.expectPeek(0xF8, 0x00)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x29, 0xE2},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x29, 0xE2, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x29, 0xE2,
+		},
+	)
+
+	s.Require().NoError(s.device.Poke(s.mock.ctx, 0x0FF8, 0xE2))
+}
+
+// expectSetMSB expects the MSB of the memory address to be set.
+func (s *DeviceTestSuite) expectSetMSB(msb byte) {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x28, msb},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x28, msb, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x28, msb,
+		},
+	)
+}
+
+// expectPeek expects the byte at lsb within the current MSB to be read and answers with value.
+func (s *DeviceTestSuite) expectPeek(lsb, value byte) {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x2B, lsb},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x2B, lsb, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2B, value,
+		},
+	)
+}
+
+// expectI2CS answers the engine version request with a NAK, which only i2cs devices send.
+func (s *DeviceTestSuite) expectI2CS() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x0D, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x0D, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0xAB, 0x0D, 0xFF,
+		},
+	)
+}
+
+func TestDeviceSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &DeviceTestSuite{})
+}
@@ -0,0 +1,89 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// These are the commands understood by energy meters.
+const (
+	cmdMeterReset  byte = 0x80
+	cmdMeterStatus byte = 0x82
+)
+
+const (
+	// meterEnergyRollover is the counter value at which the meter has wrapped around and starts over.
+	meterEnergyRollover uint32 = 254 << 24
+	// meterEnergyScale converts the energy counter into kWh, each count is 65535 watt-ticks of a 60Hz cycle.
+	meterEnergyScale = 65535.0 / (1000 * 60 * 60 * 60)
+)
+
+// EnergyReading is a single reading from an energy meter.
+type EnergyReading struct {
+	// Time is when the reading was received.
+	Time time.Time
+	// Watts is the instantaneous power being used.
+	Watts int
+	// KWh is the energy used since the meter was last reset.
+	KWh float64
+	// Counter is the raw energy counter KWh is derived from.
+	Counter uint32
+}
+
+func (r *EnergyReading) String() string {
+	return fmt.Sprintf("Time=%s, Watts=%d, KWh=%.3f", r.Time.Format(time.RFC3339), r.Watts, r.KWh)
+}
+
+// fromBytes parses the data of the meter's status response.
+func (r *EnergyReading) fromBytes(data []byte) {
+	// Power is in D7-D8 and is signed, negative when the load is feeding power back.
+	r.Watts = int(int16(uint16(data[6])<<8 | uint16(data[7])))
+
+	// The energy counter is in D9-D12.
+	r.Counter = uint32(data[8])<<24 + uint32(data[9])<<16 + uint32(data[10])<<8 + uint32(data[11])
+	if r.Counter >= meterEnergyRollover {
+		r.Counter = 0
+	}
+
+	r.KWh = float64(r.Counter) * meterEnergyScale
+}
+
+// EnergyMeter represents an energy meter such as the iMeter Solo [2423A1].
+type EnergyMeter struct {
+	*Device
+}
+
+// NewEnergyMeter creates a new energy meter by raw address.
+func NewEnergyMeter(hub Hub, addr Address) (*EnergyMeter, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EnergyMeter{Device: dev}, nil
+}
+
+// GetReading reads the current power and accumulated energy from the meter.
+func (m *EnergyMeter) GetReading(ctx context.Context) (*EnergyReading, error) {
+	if _, err := m.sendMessage(ctx, cmdMeterStatus, 0); err != nil {
+		return nil, err
+	}
+
+	rsp, err := m.expectExtended(ctx, cmdMeterStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	reading := &EnergyReading{Time: time.Now()}
+	reading.fromBytes(rsp.Data())
+
+	return reading, nil
+}
+
+// Reset clears the meter's accumulated energy.
+func (m *EnergyMeter) Reset(ctx context.Context) error {
+	_, err := m.sendMessage(ctx, cmdMeterReset, 0)
+
+	return err
+}
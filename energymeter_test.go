@@ -0,0 +1,80 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type EnergyMeterTestSuite struct {
+	suite.Suite
+	mock  *InsteonHubMock
+	meter *insteon.EnergyMeter
+}
+
+func (s *EnergyMeterTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.meter, err = insteon.NewEnergyMeter(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *EnergyMeterTestSuite) TestGetReading() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x82, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x82, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x82, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x82, 0x00,
+			0, 0, 0, 0, 0, 0, 0x00, 0x64, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00,
+		},
+	)
+
+	reading, err := s.meter.GetReading(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(100, reading.Watts)
+	s.Require().Equal(uint32(0x100000), reading.Counter)
+	s.Require().InDelta(318.15, reading.KWh, 0.01)
+	s.Require().False(reading.Time.IsZero())
+}
+
+func (s *EnergyMeterTestSuite) TestGetReadingRollover() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x82, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x82, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x82, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x82, 0x00,
+			0, 0, 0, 0, 0, 0, 0xFF, 0xFF, 0xFE, 0x00, 0x00, 0x01, 0x00, 0x00,
+		},
+	)
+
+	reading, err := s.meter.GetReading(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(-1, reading.Watts)
+	s.Require().Equal(uint32(0), reading.Counter)
+	s.Require().Zero(reading.KWh)
+}
+
+func (s *EnergyMeterTestSuite) TestReset() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x80, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x80, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x80, 0x00,
+		},
+	)
+
+	s.Require().NoError(s.meter.Reset(s.mock.ctx))
+}
+
+func TestEnergyMeterSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &EnergyMeterTestSuite{})
+}
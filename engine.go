@@ -0,0 +1,127 @@
+package insteon
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// cmdControlEngineVersion asks a device which version of the Insteon engine it runs.
+const cmdControlEngineVersion byte = 0x0D
+
+// ErrChecksum indicates an extended message arrived with a checksum that doesn't match its contents.
+var ErrChecksum = errors.New("extended message checksum mismatch")
+
+// EngineVersion is the version of the Insteon engine a device runs, which decides how its extended messages are
+// checksummed.
+type EngineVersion byte
+
+const (
+	// EngineVersionI1 devices predate extended message checksums.
+	EngineVersionI1 EngineVersion = 0x00
+	// EngineVersionI2 devices accept a single byte checksum in the last data byte.
+	EngineVersionI2 EngineVersion = 0x01
+	// EngineVersionI2CS devices require a checksum on every extended message, a two byte CRC in the last two data bytes
+	// for most commands.
+	EngineVersionI2CS EngineVersion = 0x02
+)
+
+func (v EngineVersion) String() string {
+	switch v {
+	case EngineVersionI1:
+		return "i1"
+	case EngineVersionI2:
+		return "i2"
+	case EngineVersionI2CS:
+		return "i2cs"
+	default:
+		return "Unknown"
+	}
+}
+
+// GetEngineVersion gets the version of the Insteon engine the device runs. The version is only requested once and
+// cached for the lifetime of the Device.
+func (d *Device) GetEngineVersion(ctx context.Context) (EngineVersion, error) {
+	d.cacheMu.Lock()
+	defer d.cacheMu.Unlock()
+
+	if d.engine != nil {
+		return *d.engine, nil
+	}
+
+	version := EngineVersionI2CS
+
+	// i2cs devices refuse to answer modems they aren't linked to, which is the only way to get a NAK here.
+	rsp, err := d.sendMessage(ctx, cmdControlEngineVersion, 0)
+	if err == nil {
+		version = EngineVersion(rsp.Cmd2())
+	} else if errors.Cause(err) != ErrNAK {
+		return 0, err
+	}
+
+	d.engine = &version
+
+	return version, nil
+}
+
+// cachedEngineVersion returns the engine version if it's already known.
+func (d *Device) cachedEngineVersion() (EngineVersion, bool) {
+	d.cacheMu.Lock()
+	defer d.cacheMu.Unlock()
+
+	if d.engine == nil {
+		return 0, false
+	}
+
+	return *d.engine, true
+}
+
+// usesCRC16 returns true if extended messages with the given command sent to a device running version need a two
+// byte CRC rather than the single byte checksum. All-Link database commands always use the single byte checksum.
+func usesCRC16(version EngineVersion, cmd1 byte) bool {
+	return version == EngineVersionI2CS && cmd1 != cmdControlAllLink
+}
+
+// calculateCRC16 calculates the two byte CRC i2cs devices expect over cmd1, cmd2 and the first 12 data bytes.
+func calculateCRC16(buf []byte) uint16 {
+	crc := uint16(0)
+
+	for _, c := range buf {
+		for bit := 0; bit < 8; bit++ {
+			fb := uint16(c & 0x01)
+
+			for _, tap := range []uint16{0x8000, 0x4000, 0x1000, 0x0008} {
+				if crc&tap > 0 {
+					fb ^= 1
+				}
+			}
+
+			crc = crc<<1 | fb
+			c >>= 1
+		}
+	}
+
+	return crc
+}
+
+// checkChecksum verifies the checksum of an extended message from the device. Only i2cs devices checksum what they
+// send, so messages from other devices, or from devices whose engine version isn't known yet, are accepted as is.
+// Either a valid single byte checksum or a valid CRC is accepted.
+func (d *Device) checkChecksum(rsp *ExtCommandResponse) error {
+	if version, ok := d.cachedEngineVersion(); !ok || version != EngineVersionI2CS {
+		return nil
+	}
+
+	data := rsp.data
+
+	if data[13] == calculateCRC(append([]byte{rsp.cmd1, rsp.cmd2}, data[:13]...)) {
+		return nil
+	}
+
+	if crc := calculateCRC16(append([]byte{rsp.cmd1, rsp.cmd2}, data[:12]...)); data[12] == byte(crc>>8) &&
+		data[13] == byte(crc) {
+		return nil
+	}
+
+	return errors.Wrapf(ErrChecksum, "address: %s, cmd1: %x, cmd2: %x", rsp.from, rsp.cmd1, rsp.cmd2)
+}
@@ -90,7 +90,7 @@ type ExtCommandResponse struct {
 
 func (cr *ExtCommandResponse) fromBytes(buffer []byte) {
 	cr.StdCommandResponse.fromBytes(buffer)
-	copy(cr.data[:], buffer[11:24])
+	copy(cr.data[:], buffer[11:25])
 }
 
 func (cr *ExtCommandResponse) Data() []byte {
@@ -0,0 +1,194 @@
+package insteon
+
+import (
+	"context"
+)
+
+// These are the channels exposed by a FanLinc.
+const (
+	fanLincGroupLight byte = 1
+	fanLincGroupFan   byte = 2
+
+	fanLincStatusChannelFan byte = 3
+)
+
+// FanSpeed is the speed of a fan attached to a FanLinc.
+type FanSpeed int
+
+const (
+	FanSpeedOff FanSpeed = iota
+	FanSpeedLow
+	FanSpeedMedium
+	FanSpeedHigh
+)
+
+// Level returns the on-level the FanLinc uses for this speed.
+func (fs FanSpeed) Level() byte {
+	switch fs {
+	case FanSpeedLow:
+		return 0x55
+	case FanSpeedMedium:
+		return 0xAA
+	case FanSpeedHigh:
+		return 0xFF
+	case FanSpeedOff:
+		fallthrough
+	default:
+		return 0x00
+	}
+}
+
+func (fs FanSpeed) String() string {
+	switch fs {
+	case FanSpeedOff:
+		return "Off"
+	case FanSpeedLow:
+		return "Low"
+	case FanSpeedMedium:
+		return "Medium"
+	case FanSpeedHigh:
+		return "High"
+	default:
+		return "Unknown"
+	}
+}
+
+// fanSpeedFromLevel maps an on-level reported by the FanLinc back to a speed.
+func fanSpeedFromLevel(level byte) FanSpeed {
+	switch {
+	case level == 0:
+		return FanSpeedOff
+	case level <= 0x7F:
+		return FanSpeedLow
+	case level <= 0xBF:
+		return FanSpeedMedium
+	default:
+		return FanSpeedHigh
+	}
+}
+
+// FanLincChannel identifies which half of a FanLinc an event refers to.
+type FanLincChannel byte
+
+const (
+	FanLincChannelLight = FanLincChannel(fanLincGroupLight)
+	FanLincChannelFan   = FanLincChannel(fanLincGroupFan)
+)
+
+// FanLincEvent is generated when a FanLinc reports its light or fan changing state.
+type FanLincEvent struct {
+	deviceEvent
+	// Channel is the part of the FanLinc that changed.
+	Channel FanLincChannel
+	// Level is the new on-level of the channel.
+	Level byte
+}
+
+// Speed returns the fan speed corresponding to the level in this event.
+func (e *FanLincEvent) Speed() FanSpeed {
+	return fanSpeedFromLevel(e.Level)
+}
+
+// FanLinc represents a FanLinc [2475F] ceiling fan controller. The light is group 1 and behaves like any other
+// dimmable device, the fan is group 2 and is controlled by speed.
+type FanLinc struct {
+	*Device
+	events deviceEvents
+}
+
+// NewFanLinc creates a new FanLinc by raw address.
+func NewFanLinc(hub Hub, addr Address) (*FanLinc, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FanLinc{Device: dev}, nil
+}
+
+// Light returns the light channel of the FanLinc.
+func (f *FanLinc) Light() *Device {
+	return f.Device
+}
+
+// Fan returns the fan channel of the FanLinc.
+func (f *FanLinc) Fan() *FanLincFan {
+	return &FanLincFan{fanLinc: f}
+}
+
+// SetFanSpeed sets the speed of the fan, FanSpeedOff turns the fan off.
+func (f *FanLinc) SetFanSpeed(ctx context.Context, speed FanSpeed) error {
+	ctlCmd := cmdControlOn
+	if speed == FanSpeedOff {
+		ctlCmd = cmdControlOff
+	}
+
+	_, err := f.sendExtended(ctx, ctlCmd, speed.Level(), [14]byte{fanLincGroupFan})
+
+	return err
+}
+
+// GetFanSpeed gets the current speed of the fan.
+func (f *FanLinc) GetFanSpeed(ctx context.Context) (FanSpeed, error) {
+	status, err := f.GetStatusChannel(ctx, fanLincStatusChannelFan)
+	if err != nil {
+		return FanSpeedOff, err
+	}
+
+	return fanSpeedFromLevel(status.Level), nil
+}
+
+// AddListener registers a listener that's notified with a FanLincEvent whenever the light or fan changes state.
+func (f *FanLinc) AddListener(listener DeviceEventListener) {
+	f.events.add(listener)
+	f.events.watch(f.hub, f.address, f.handleMessage)
+}
+
+func (f *FanLinc) handleMessage(rsp CommandResponse) {
+	group, ok := groupBroadcast(rsp)
+	if !ok || (group != fanLincGroupLight && group != fanLincGroupFan) {
+		return
+	}
+
+	evt := &FanLincEvent{deviceEvent: deviceEvent{from: f.address}, Channel: FanLincChannel(group)}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn, cmdControlFastOn:
+		evt.Level = rsp.Cmd2()
+		if evt.Level == 0 {
+			// Broadcasts don't always carry the level, assume fully on.
+			evt.Level = 0xFF
+		}
+	case cmdControlOff, cmdControlFastOff:
+		evt.Level = 0
+	default:
+		return
+	}
+
+	f.events.emit(evt)
+}
+
+// FanLincFan is the fan channel of a FanLinc.
+type FanLincFan struct {
+	fanLinc *FanLinc
+}
+
+// TurnOn turns the fan on at high speed.
+func (fan *FanLincFan) TurnOn(ctx context.Context) error {
+	return fan.fanLinc.SetFanSpeed(ctx, FanSpeedHigh)
+}
+
+// TurnOff turns the fan off.
+func (fan *FanLincFan) TurnOff(ctx context.Context) error {
+	return fan.fanLinc.SetFanSpeed(ctx, FanSpeedOff)
+}
+
+// SetSpeed sets the speed of the fan.
+func (fan *FanLincFan) SetSpeed(ctx context.Context, speed FanSpeed) error {
+	return fan.fanLinc.SetFanSpeed(ctx, speed)
+}
+
+// GetSpeed gets the current speed of the fan.
+func (fan *FanLincFan) GetSpeed(ctx context.Context) (FanSpeed, error) {
+	return fan.fanLinc.GetFanSpeed(ctx)
+}
@@ -0,0 +1,91 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type FanLincTestSuite struct {
+	suite.Suite
+	mock    *InsteonHubMock
+	fanLinc *insteon.FanLinc
+}
+
+func (s *FanLincTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.fanLinc, err = insteon.NewFanLinc(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *FanLincTestSuite) TestSetFanSpeed() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x11, 0x55, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x96},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x11, 0x55, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x96, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x11, 0x55,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x13, 0x00, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xE9},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x13, 0x00, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xE9, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x13, 0x00,
+		},
+	)
+
+	s.Require().NoError(s.fanLinc.SetFanSpeed(s.mock.ctx, insteon.FanSpeedLow))
+	s.Require().NoError(s.fanLinc.Fan().TurnOff(s.mock.ctx))
+}
+
+func (s *FanLincTestSuite) TestGetFanSpeed() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x03},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x03, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x00, 0xAA,
+		},
+	)
+
+	speed, err := s.fanLinc.GetFanSpeed(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(insteon.FanSpeedMedium, speed)
+}
+
+func (s *FanLincTestSuite) TestEvents() {
+	events := make(chan *insteon.FanLincEvent, 2)
+
+	s.fanLinc.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.FanLincEvent)
+	})
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x02, 0xCF, 0x11, 0xAA,
+		})
+	}()
+
+	var evt *insteon.FanLincEvent
+
+	select {
+	case evt = <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Equal(insteon.Address{0xAA, 0xBB, 0xCC}, evt.From())
+	s.Require().Equal(insteon.FanLincChannelFan, evt.Channel)
+	s.Require().Equal(insteon.FanSpeedMedium, evt.Speed())
+}
+
+func TestFanLincSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &FanLincTestSuite{})
+}
@@ -32,7 +32,8 @@ type Hub interface {
 	SendX10(context.Context, X10Raw, X10Flags) error
 	// SendGroupCommand sends a group command to the network this Hub is connected to.
 	SendGroupCommand(ctx context.Context, hostCmd byte, group byte) error
-	// AddEventListener registers a listener interested in events coming from this Hub.
+	// AddEventListener registers a listener interested in events coming from this Hub. Events are passed to each
+	// listener in the order they arrive.
 	AddEventListener(EventListener)
 	// RemoveEventListener removes a previously registered EventListener.
 	RemoveEventListener(EventListener)
@@ -96,3 +96,80 @@ func (mock *InsteonHubMock) Write(p []byte) (int, error) {
 func (mock *InsteonHubMock) Close() error {
 	return nil
 }
+
+// ExpectEngineVersion expects the engine version request sent to AA.BB.CC before its first extended message and
+// answers it with version.
+func (mock *InsteonHubMock) ExpectEngineVersion(version byte) {
+	mock.ExpectDeviceEngineVersion(insteon.Address{0xAA, 0xBB, 0xCC}, version)
+}
+
+// ExpectDeviceEngineVersion expects the engine version request sent to addr and answers it with version.
+func (mock *InsteonHubMock) ExpectDeviceEngineVersion(addr insteon.Address, version byte) {
+	mock.Expect(
+		[]byte{0x02, 0x62, addr[0], addr[1], addr[2], 0x0F, 0x0D, 0x00},
+		[]byte{
+			0x02, 0x62, addr[0], addr[1], addr[2], 0x0F, 0x0D, 0x00, 0x06,
+			0x02, 0x50, addr[0], addr[1], addr[2], 0x01, 0x02, 0x03, 0x2B, 0x0D, version,
+		},
+	)
+}
+
+// ExpectExtended expects an extended message to be sent to addr, filling in its checksum, and answers with the
+// modem's echo, the device's acknowledgement and then rsp.
+func (mock *InsteonHubMock) ExpectExtended(addr insteon.Address, cmd1, cmd2 byte, data []byte, rsp ...[]byte) {
+	req := append([]byte{0x02, 0x62, addr[0], addr[1], addr[2], 0x3F, cmd1, cmd2}, make([]byte, 14)...)
+	copy(req[8:21], data)
+	req[21] = checksum(req[6:21])
+
+	echo := append(append([]byte{}, req...), 0x06)
+	echo = append(echo, 0x02, 0x50, addr[0], addr[1], addr[2], 0x01, 0x02, 0x03, 0x2B, cmd1, cmd2)
+
+	for _, r := range rsp {
+		echo = append(echo, r...)
+	}
+
+	mock.Expect(req, echo)
+}
+
+// ExpectDatabase expects addr's database to be read and answers with records, each the address of a record followed
+// by its 8 bytes, and then the high water record after the last of them.
+func (mock *InsteonHubMock) ExpectDatabase(addr insteon.Address, records ...[]byte) {
+	end := []byte{0x0F, 0xFF, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	rsp := make([][]byte, 0, len(records)+1)
+
+	for _, rec := range records {
+		rsp = append(rsp, recordMessage(addr, rec))
+
+		memAddr := (uint16(rec[0])<<8 | uint16(rec[1])) - 8
+		end[0], end[1] = byte(memAddr>>8), byte(memAddr)
+	}
+
+	mock.ExpectExtended(addr, 0x2F, 0x00, nil, append(rsp, recordMessage(addr, end))...)
+}
+
+// ExpectRecordWrite expects a record of addr's database to be written and then read back as readBack, both given as
+// the address of the record followed by its 8 bytes.
+func (mock *InsteonHubMock) ExpectRecordWrite(addr insteon.Address, rec, readBack []byte) {
+	mock.ExpectExtended(addr, 0x2F, 0x00, append([]byte{0x00, 0x02, rec[0], rec[1], 0x00}, rec[2:]...))
+	mock.ExpectExtended(addr, 0x2F, 0x00, []byte{0x00, 0x00, rec[0], rec[1], 0x01}, recordMessage(addr, readBack))
+}
+
+// recordMessage builds the extended message addr sends with a record of its database.
+func recordMessage(addr insteon.Address, rec []byte) []byte {
+	msg := []byte{0x02, 0x51, addr[0], addr[1], addr[2], 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00, 0x00, 0x01, rec[0], rec[1], 0x00}
+	msg = append(msg, rec[2:]...)
+
+	return append(msg, 0x00)
+}
+
+// checksum calculates the single byte checksum of an extended message over cmd1, cmd2 and the first 13 data bytes.
+func checksum(buf []byte) byte {
+	sum := byte(1)
+
+	for _, b := range buf {
+		sum += b
+	}
+
+	return ^sum
+}
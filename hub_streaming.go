@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"io"
+	"sync"
 	"time"
+	"unsafe"
 
 	"github.com/pkg/errors"
 )
@@ -13,13 +15,18 @@ var ErrUnexpectedAckByte = errors.New("unexpected acknowledgement byte")
 
 // HubStreaming is a generic hub implementation that assumes we have a bi-directional data stream to the PLM modem.
 type HubStreaming struct {
-	stream    io.ReadWriteCloser
-	buffer    []byte
-	errChan   chan error
-	events    chan Event
+	stream  io.ReadWriteCloser
+	buffer  []byte
+	errChan chan error
+	events  chan Event
+	logger  CommLogger
+
+	// ackMu guards ackBuffer, which commands add to and the reader takes from.
+	ackMu     sync.Mutex
 	ackBuffer []expectAck
-	listeners []EventListener
-	logger    CommLogger
+
+	listenerMu sync.Mutex
+	listeners  []*listenerQueue
 }
 
 // NewHubStreaming creates a new streaming hub implementation around the passed in stream implementation.
@@ -250,11 +257,18 @@ func (hub *HubStreaming) Reset(ctx context.Context) error {
 }
 
 func (hub *HubStreaming) Expect(ctx context.Context, evt Event) (Event, error) {
+	return hub.expectAny(ctx, evt)
+}
+
+// expectAny is like Expect, but returns the first event matching the ID of any of the given events.
+func (hub *HubStreaming) expectAny(ctx context.Context, evts ...Event) (Event, error) {
 	for {
 		select {
 		case e := <-hub.events:
-			if e.ID() == evt.ID() {
-				return e, nil
+			for _, evt := range evts {
+				if e.ID() == evt.ID() {
+					return e, nil
+				}
 			}
 		case err := <-hub.errChan:
 			return nil, err
@@ -342,24 +356,98 @@ func (hub *HubStreaming) GetLastSender(ctx context.Context) (*AllLinkRecord, err
 	}
 }
 
+// AddEventListener registers a listener that's called with every event the hub receives. Each listener is called with
+// one event at a time, in the order they were received, so a listener that's busy doesn't see events out of order but
+// doesn't hold up the hub or other listeners either.
 func (hub *HubStreaming) AddEventListener(listener EventListener) {
-	hub.listeners = append(hub.listeners, listener)
+	hub.listenerMu.Lock()
+	defer hub.listenerMu.Unlock()
+
+	hub.listeners = append(hub.listeners, &listenerQueue{listener: listener})
 }
 
+// RemoveEventListener removes a listener registered with AddEventListener. Functions can't be compared, so listener has
+// to be the same function value that was registered, not just the same function.
 func (hub *HubStreaming) RemoveEventListener(listener EventListener) {
+	hub.listenerMu.Lock()
+	defer hub.listenerMu.Unlock()
+
 	for idx := len(hub.listeners) - 1; idx >= 0; idx-- {
-		if &hub.listeners[idx] == &listener {
+		if listenerID(hub.listeners[idx].listener) == listenerID(listener) {
 			hub.listeners = append(hub.listeners[:idx], hub.listeners[idx+1:]...)
 		}
 	}
 }
 
+// listenerID identifies a function value by the closure it points at, which every copy of the value shares.
+func listenerID(listener EventListener) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&listener))
+}
+
+// notify passes an event, or the error that stopped the hub, to every listener.
+func (hub *HubStreaming) notify(evt Event, err error) {
+	hub.listenerMu.Lock()
+	defer hub.listenerMu.Unlock()
+
+	for _, q := range hub.listeners {
+		q.push(evt, err)
+	}
+}
+
+// listenerQueue delivers events to a listener one at a time and in order, from a goroutine that only runs while there
+// are events waiting.
+type listenerQueue struct {
+	listener EventListener
+
+	mu      sync.Mutex
+	pending []listenerEvent
+	running bool
+}
+
+type listenerEvent struct {
+	evt Event
+	err error
+}
+
+func (q *listenerQueue) push(evt Event, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, listenerEvent{evt: evt, err: err})
+
+	if !q.running {
+		q.running = true
+
+		go q.deliver()
+	}
+}
+
+func (q *listenerQueue) deliver() {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mu.Unlock()
+
+			return
+		}
+
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		q.listener(next.evt, next.err)
+	}
+}
+
 func (hub *HubStreaming) SetCommLogger(logger CommLogger) {
 	hub.logger = logger
 }
 
 func (hub *HubStreaming) directIMCommand(ctx context.Context, cmd []byte, expect int) ([]byte, error) {
+	hub.ackMu.Lock()
 	hub.ackBuffer = append(hub.ackBuffer, expectAck{cmd: cmd, length: expect})
+	hub.ackMu.Unlock()
 
 	if hub.logger != nil {
 		hub.logger(CommDirectionHostToIM, cmd)
@@ -421,10 +509,7 @@ func (hub *HubStreaming) read() {
 		if err != nil {
 			hub.errChan <- err
 
-			// Notify listeners
-			for _, l := range hub.listeners {
-				go l(nil, err)
-			}
+			hub.notify(nil, err)
 
 			return
 		}
@@ -441,19 +526,17 @@ func (hub *HubStreaming) read() {
 
 func (hub *HubStreaming) handleACK() bool {
 	// First we need to check to see if we're waiting for any acks.
-	if len(hub.ackBuffer) > 0 {
+	if expected, ok := hub.nextAck(); ok {
 		// NAK's don't always echo commands, if our buffer starts with NAK, explode.
 		if len(hub.buffer) > 0 && hub.buffer[0] == serialNAK {
-			hub.ackBuffer = hub.ackBuffer[1:]
+			hub.popAck()
 			ack := &Ack{Response: []byte{serialNAK}, Type: serialNAK}
 			hub.buffer = hub.buffer[1:]
-			hub.events <- ack
+			hub.queueEvent(ack)
 
 			return true
 		}
 
-		expected := hub.ackBuffer[0]
-
 		idx := bytes.Index(hub.buffer, expected.cmd)
 		if idx < 0 {
 			return true
@@ -462,11 +545,11 @@ func (hub *HubStreaming) handleACK() bool {
 		}
 
 		// Got it
-		hub.ackBuffer = hub.ackBuffer[1:]
+		hub.popAck()
 		ack := &Ack{}
 		ack.fromBytes(hub.buffer[idx : idx+expected.length])
 		hub.buffer = hub.buffer[idx+expected.length:]
-		hub.events <- ack
+		hub.queueEvent(ack)
 
 		hub.parseBuffer()
 	}
@@ -474,6 +557,26 @@ func (hub *HubStreaming) handleACK() bool {
 	return false
 }
 
+// nextAck returns the acknowledgement the oldest command is waiting for, if there is one.
+func (hub *HubStreaming) nextAck() (expectAck, bool) {
+	hub.ackMu.Lock()
+	defer hub.ackMu.Unlock()
+
+	if len(hub.ackBuffer) == 0 {
+		return expectAck{}, false
+	}
+
+	return hub.ackBuffer[0], true
+}
+
+// popAck drops the acknowledgement returned by nextAck once it's been handled.
+func (hub *HubStreaming) popAck() {
+	hub.ackMu.Lock()
+	defer hub.ackMu.Unlock()
+
+	hub.ackBuffer = hub.ackBuffer[1:]
+}
+
 func (hub *HubStreaming) parseBuffer() {
 	// We need to handle ACKs first.
 	if hub.handleACK() {
@@ -503,12 +606,9 @@ func (hub *HubStreaming) parseBuffer() {
 
 	imCmd.fromBytes(hub.buffer[idx : idx+imCmd.Length()])
 
-	hub.events <- imCmd
+	hub.queueEvent(imCmd)
 
-	// Notify listeners
-	for _, l := range hub.listeners {
-		go l(imCmd, nil)
-	}
+	hub.notify(imCmd, nil)
 
 	// Move buffer forward
 	hub.buffer = hub.buffer[idx+imCmd.Length():]
@@ -517,6 +617,59 @@ func (hub *HubStreaming) parseBuffer() {
 	hub.parseBuffer()
 }
 
+// queueEvent queues an event for anyone waiting on a response. Broadcasts from devices pile up when nobody is waiting,
+// so once the queue is full the oldest queued broadcast is discarded to make room, which keeps both acknowledgements and
+// the latest broadcasts, like the one a device sends when its SET button is pressed. A broadcast is only discarded
+// itself when nothing but replies are queued, anything else discards the oldest queued event instead.
+func (hub *HubStreaming) queueEvent(evt Event) {
+	select {
+	case hub.events <- evt:
+		return
+	default:
+	}
+
+	// The reader is the only one queueing events, so everything taken out fits back in.
+	queued := make([]Event, 0, cap(hub.events)+1)
+
+	for drained := false; !drained; {
+		select {
+		case e := <-hub.events:
+			queued = append(queued, e)
+		default:
+			drained = true
+		}
+	}
+
+	dropped := false
+
+	for idx, e := range queued {
+		if isBroadcast(e) {
+			queued = append(queued[:idx], queued[idx+1:]...)
+			dropped = true
+
+			break
+		}
+	}
+
+	switch {
+	case dropped || len(queued) < cap(hub.events):
+		queued = append(queued, evt)
+	case !isBroadcast(evt):
+		queued = append(queued[1:], evt)
+	}
+
+	for _, e := range queued {
+		hub.events <- e
+	}
+}
+
+// isBroadcast returns true if the event is a broadcast from a device rather than a message sent to the modem.
+func isBroadcast(evt Event) bool {
+	rsp, ok := evt.(CommandResponse)
+
+	return ok && rsp.Flags().BroadcastNAK() && !rsp.Flags().Acknowledgement()
+}
+
 func imCommand(cmd byte) Event {
 	cmdList := []Event{
 		&StdCommandResponse{},
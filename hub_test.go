@@ -1,7 +1,9 @@
 package insteon_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/suite"
@@ -232,6 +234,77 @@ func (s *HubTestSuite) TestNotReady() {
 	s.Require().ErrorIs(err, insteon.ErrNotReady)
 }
 
+func (s *HubTestSuite) TestAckSurvivesBroadcasts() {
+	rsp := []byte{
+		0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x00, 0x06,
+		0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x00, 0x7F,
+	}
+
+	// Flood the queue with broadcasts from another device before anyone gets to read the acknowledgement.
+	for idx := 0; idx < 2*insteon.ChannelBufferSize; idx++ {
+		rsp = append(rsp, 0x02, 0x50, 0x11, 0x22, 0x33, 0x00, 0x00, 0x01, 0xCF, 0x11, 0x00)
+	}
+
+	s.mock.Expect([]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x00}, rsp)
+
+	dev, err := insteon.NewDevice(s.hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(s.mock.ctx, 2*time.Second)
+	defer cancel()
+
+	status, err := dev.GetStatus(ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(byte(0x7F), status.Level)
+}
+
+func (s *HubTestSuite) TestLatestBroadcastSurvivesBroadcasts() {
+	rsp := []byte{
+		0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00, 0x06,
+		0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x10, 0x00,
+	}
+
+	// The queue overflows with broadcasts from another device before the SET button broadcast Identify waits for.
+	for idx := 0; idx < 2*insteon.ChannelBufferSize; idx++ {
+		rsp = append(rsp, 0x02, 0x50, 0x11, 0x22, 0x33, 0x00, 0x00, 0x01, 0xCF, 0x11, 0x00)
+	}
+
+	rsp = append(rsp, 0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45, 0x8B, 0x01, 0x00)
+
+	s.mock.Expect([]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00}, rsp)
+
+	dev, err := insteon.NewDevice(s.hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(s.mock.ctx, 2*time.Second)
+	defer cancel()
+
+	id, err := dev.Identify(ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(&insteon.DeviceIdentification{Category: 0x01, SubCategory: 0x2E, Firmware: 0x45}, id)
+}
+
+func (s *HubTestSuite) TestRemoveEventListener() {
+	removed, kept := make(chan insteon.Event, 1), make(chan insteon.Event, 1)
+
+	remove := func(evt insteon.Event, err error) { removed <- evt }
+	s.hub.AddEventListener(remove)
+	s.hub.AddEventListener(func(evt insteon.Event, err error) { kept <- evt })
+	s.hub.RemoveEventListener(remove)
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0x11, 0x22, 0x33, 0x00, 0x00, 0x01, 0xCF, 0x11, 0x00})
+	}()
+
+	select {
+	case <-kept:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Empty(removed)
+}
+
 func TestHubSuite(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,242 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// These are the status channels and groups used by an IOLinc.
+const (
+	ioLincStatusChannelRelay  byte = 0
+	ioLincStatusChannelSensor byte = 1
+	ioLincGroupSensor         byte = 1
+)
+
+// These are the operating flag values used to configure an IOLinc.
+const (
+	ioLincFlagRelayFollowsInputOn  byte = 0x04
+	ioLincFlagRelayFollowsInputOff byte = 0x05
+	ioLincFlagMomentaryOn          byte = 0x06
+	ioLincFlagMomentaryOff         byte = 0x07
+	ioLincFlagMomentaryBOn         byte = 0x12
+	ioLincFlagMomentaryBOff        byte = 0x13
+	ioLincFlagMomentaryCOn         byte = 0x14
+	ioLincFlagMomentaryCOff        byte = 0x15
+)
+
+// These bits are reported in the IOLinc's operating flags.
+const (
+	ioLincOpFlagRelayFollowsInput DeviceOpFlags = 0x04
+	ioLincOpFlagMomentaryA        DeviceOpFlags = 0x08
+	ioLincOpFlagMomentaryB        DeviceOpFlags = 0x10
+	ioLincOpFlagMomentaryC        DeviceOpFlags = 0x80
+)
+
+// ioLincConfigMomentaryTime is the extended configuration setting holding the momentary duration in tenths of a
+// second, the current value is reported in the third data byte.
+const (
+	ioLincConfigMomentaryTime byte = 0x06
+	ioLincDataMomentaryTime        = 2
+)
+
+// IOLincMode describes how the IOLinc's relay behaves when it's turned on.
+type IOLincMode int
+
+const (
+	// IOLincModeLatching keeps the relay in whatever state it was last told to be in.
+	IOLincModeLatching IOLincMode = iota
+	// IOLincModeMomentaryA closes the relay momentarily for either an on or off command, depending on how the link
+	// was created.
+	IOLincModeMomentaryA
+	// IOLincModeMomentaryB closes the relay momentarily for both on and off commands.
+	IOLincModeMomentaryB
+	// IOLincModeMomentaryC closes the relay momentarily depending on the state of the sensor input.
+	IOLincModeMomentaryC
+)
+
+func (m IOLincMode) String() string {
+	switch m {
+	case IOLincModeLatching:
+		return "Latching"
+	case IOLincModeMomentaryA:
+		return "Momentary A"
+	case IOLincModeMomentaryB:
+		return "Momentary B"
+	case IOLincModeMomentaryC:
+		return "Momentary C"
+	default:
+		return "Unknown"
+	}
+}
+
+// IOLincConfig is the current configuration of an IOLinc.
+type IOLincConfig struct {
+	// Mode is the relay mode.
+	Mode IOLincMode
+	// RelayFollowsInput indicates the relay is turned on and off along with the sensor input.
+	RelayFollowsInput bool
+	// MomentaryDuration is how long the relay stays closed in the momentary modes.
+	MomentaryDuration time.Duration
+}
+
+func (c *IOLincConfig) String() string {
+	return fmt.Sprintf("Mode=%s, RelayFollowsInput=%t, MomentaryDuration=%s",
+		c.Mode, c.RelayFollowsInput, c.MomentaryDuration)
+}
+
+// IOLincEvent is generated when an IOLinc reports its sensor input changing state.
+type IOLincEvent struct {
+	deviceEvent
+	// Sensor is true if the sensor input is on (closed).
+	Sensor bool
+}
+
+// IOLinc represents an IOLinc [2450] relay and sensor module. The embedded device controls the relay.
+type IOLinc struct {
+	*Device
+	events deviceEvents
+}
+
+// NewIOLinc creates a new IOLinc by raw address.
+func NewIOLinc(hub Hub, addr Address) (*IOLinc, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IOLinc{Device: dev}, nil
+}
+
+// SetRelay turns the relay on or off.
+func (io *IOLinc) SetRelay(ctx context.Context, on bool) error {
+	if on {
+		return io.TurnOn(ctx)
+	}
+
+	return io.TurnOff(ctx)
+}
+
+// GetRelay returns true if the relay is currently on.
+func (io *IOLinc) GetRelay(ctx context.Context) (bool, error) {
+	status, err := io.GetStatusChannel(ctx, ioLincStatusChannelRelay)
+	if err != nil {
+		return false, err
+	}
+
+	return status.Level > 0, nil
+}
+
+// GetSensor returns true if the sensor input is currently on (closed).
+func (io *IOLinc) GetSensor(ctx context.Context) (bool, error) {
+	status, err := io.GetStatusChannel(ctx, ioLincStatusChannelSensor)
+	if err != nil {
+		return false, err
+	}
+
+	return status.Level > 0, nil
+}
+
+// GetConfig reads the relay mode and momentary duration from the IOLinc.
+func (io *IOLinc) GetConfig(ctx context.Context) (*IOLincConfig, error) {
+	flags, err := io.GetOperatingFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.GetExtendedConfig(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &IOLincConfig{
+		RelayFollowsInput: flags&ioLincOpFlagRelayFollowsInput > 0,
+		MomentaryDuration: time.Duration(data[ioLincDataMomentaryTime]) * 100 * time.Millisecond,
+	}
+
+	switch {
+	case flags&ioLincOpFlagMomentaryC > 0:
+		cfg.Mode = IOLincModeMomentaryC
+	case flags&ioLincOpFlagMomentaryB > 0:
+		cfg.Mode = IOLincModeMomentaryB
+	case flags&ioLincOpFlagMomentaryA > 0:
+		cfg.Mode = IOLincModeMomentaryA
+	default:
+		cfg.Mode = IOLincModeLatching
+	}
+
+	return cfg, nil
+}
+
+// SetMode changes the relay mode.
+func (io *IOLinc) SetMode(ctx context.Context, mode IOLincMode) error {
+	var flags []byte
+
+	switch mode {
+	case IOLincModeLatching:
+		flags = []byte{ioLincFlagMomentaryOff}
+	case IOLincModeMomentaryA:
+		flags = []byte{ioLincFlagMomentaryOn, ioLincFlagMomentaryBOff, ioLincFlagMomentaryCOff}
+	case IOLincModeMomentaryB:
+		flags = []byte{ioLincFlagMomentaryOn, ioLincFlagMomentaryBOn, ioLincFlagMomentaryCOff}
+	case IOLincModeMomentaryC:
+		flags = []byte{ioLincFlagMomentaryOn, ioLincFlagMomentaryBOff, ioLincFlagMomentaryCOn}
+	default:
+		return fmt.Errorf("unknown IOLinc mode: %d", mode)
+	}
+
+	for _, flag := range flags {
+		if err := io.setOperatingFlag(ctx, flag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetRelayFollowsInput sets whether the relay turns on and off along with the sensor input.
+func (io *IOLinc) SetRelayFollowsInput(ctx context.Context, follow bool) error {
+	if follow {
+		return io.setOperatingFlag(ctx, ioLincFlagRelayFollowsInputOn)
+	}
+
+	return io.setOperatingFlag(ctx, ioLincFlagRelayFollowsInputOff)
+}
+
+// SetMomentaryDuration sets how long the relay stays closed in the momentary modes. The IOLinc supports durations
+// between 0.1 and 25.5 seconds in tenth of a second increments.
+func (io *IOLinc) SetMomentaryDuration(ctx context.Context, duration time.Duration) error {
+	const maxTenths = 0xFF
+
+	tenths := duration / (100 * time.Millisecond)
+	if tenths < 1 || tenths > maxTenths {
+		return fmt.Errorf("momentary duration out of range: %s", duration)
+	}
+
+	return io.SetExtendedConfig(ctx, 0, ioLincConfigMomentaryTime, byte(tenths))
+}
+
+// AddListener registers a listener that's notified with an IOLincEvent whenever the sensor input changes state.
+func (io *IOLinc) AddListener(listener DeviceEventListener) {
+	io.events.add(listener)
+	io.events.watch(io.hub, io.address, io.handleMessage)
+}
+
+func (io *IOLinc) handleMessage(rsp CommandResponse) {
+	if group, ok := groupBroadcast(rsp); !ok || group != ioLincGroupSensor {
+		return
+	}
+
+	evt := &IOLincEvent{deviceEvent: deviceEvent{from: io.address}}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn, cmdControlFastOn:
+		evt.Sensor = true
+	case cmdControlOff, cmdControlFastOff:
+		evt.Sensor = false
+	default:
+		return
+	}
+
+	io.events.emit(evt)
+}
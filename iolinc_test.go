@@ -0,0 +1,125 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type IOLincTestSuite struct {
+	suite.Suite
+	mock   *InsteonHubMock
+	ioLinc *insteon.IOLinc
+}
+
+func (s *IOLincTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.ioLinc, err = insteon.NewIOLinc(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *IOLincTestSuite) TestGetSensor() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x01},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x01, 0x06,
+			// A broadcast from an unrelated device arrives before the acknowledgement.
+			0x02, 0x50, 0x11, 0x22, 0x33, 0x00, 0x00, 0x01, 0xCF, 0x13, 0x00,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x00, 0xFF,
+		},
+	)
+
+	sensor, err := s.ioLinc.GetSensor(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().True(sensor)
+}
+
+func (s *IOLincTestSuite) TestGetConfig() {
+	for flags, mode := range map[byte]insteon.IOLincMode{
+		0x00: insteon.IOLincModeLatching,
+		0x08: insteon.IOLincModeMomentaryA,
+		0x18: insteon.IOLincModeMomentaryB,
+		0x98: insteon.IOLincModeMomentaryC,
+		// X10 off is reported in the bit next to the momentary ones and mustn't be taken for a mode.
+		0x20: insteon.IOLincModeLatching,
+	} {
+		var hub insteon.Hub
+
+		hub, s.mock = newMock()
+
+		ioLinc, err := insteon.NewIOLinc(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+		s.Require().NoError(err)
+
+		s.mock.Expect(
+			[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x1F, 0x00},
+			[]byte{
+				0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x1F, 0x00, 0x06,
+				0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x1F, flags | 0x04,
+			},
+		)
+		s.mock.ExpectEngineVersion(0x01)
+		s.mock.ExpectExtended(insteon.Address{0xAA, 0xBB, 0xCC}, 0x2E, 0x00, nil, []byte{
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2E, 0x00,
+			0x00, 0x01, 0x0A, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		})
+
+		cfg, err := ioLinc.GetConfig(s.mock.ctx)
+		s.Require().NoError(err)
+		s.Require().Equal(&insteon.IOLincConfig{
+			Mode:              mode,
+			RelayFollowsInput: true,
+			MomentaryDuration: time.Second,
+		}, cfg, "flags: %02X", flags)
+		s.Require().Equal(0, s.mock.inBuffer.Len())
+	}
+}
+
+func (s *IOLincTestSuite) TestSetMomentaryDuration() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x00, 0x00, 0x06, 0x0A, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xC0},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x00, 0x00, 0x06, 0x0A, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xC0, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2E, 0x00,
+		},
+	)
+
+	s.Require().NoError(s.ioLinc.SetMomentaryDuration(s.mock.ctx, time.Second))
+	s.Require().Error(s.ioLinc.SetMomentaryDuration(s.mock.ctx, time.Minute))
+}
+
+func (s *IOLincTestSuite) TestEvents() {
+	events := make(chan *insteon.IOLincEvent, 1)
+
+	s.ioLinc.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.IOLincEvent)
+	})
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x01, 0xCF, 0x11, 0x00})
+	}()
+
+	var evt *insteon.IOLincEvent
+
+	select {
+	case evt = <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Equal(insteon.Address{0xAA, 0xBB, 0xCC}, evt.From())
+	s.Require().True(evt.Sensor)
+}
+
+func TestIOLincSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &IOLincTestSuite{})
+}
@@ -0,0 +1,218 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// These are the commands understood by irrigation controllers.
+const (
+	cmdIrrigationValveOn       byte = 0x40
+	cmdIrrigationValveOff      byte = 0x41
+	cmdIrrigationProgramOn     byte = 0x42
+	cmdIrrigationProgramOff    byte = 0x43
+	cmdIrrigationControl       byte = 0x44
+	cmdIrrigationTimersRequest byte = 0x45
+	cmdIrrigationTimers        byte = 0x46
+	cmdIrrigationValveStatus   byte = 0x27
+)
+
+// These are the cmd2 values used with cmdIrrigationControl.
+const (
+	irrigationControlGetValveStatus byte = 0x02
+	irrigationControlSensorOn       byte = 0x0C
+	irrigationControlSensorOff      byte = 0x0D
+)
+
+// These are the offsets of the sensor and rain delay settings in the extended configuration data.
+const (
+	irrigationDataFlags     = 2
+	irrigationDataRainDelay = 3
+
+	irrigationFlagSensorEnabled byte = 0x01
+)
+
+const (
+	// IrrigationValves is the number of valves an irrigation controller can drive.
+	IrrigationValves = 8
+	// IrrigationPrograms is the number of programs stored on an irrigation controller, program 0 is the default.
+	IrrigationPrograms = 5
+)
+
+// IrrigationTimers are how long each valve runs for in a program, a zero duration skips the valve.
+type IrrigationTimers [IrrigationValves]time.Duration
+
+// IrrigationStatus is the state of the valves on an irrigation controller.
+type IrrigationStatus struct {
+	// Valves is a bitmap of the valves that are currently on, bit 0 is valve 0.
+	Valves byte
+}
+
+// ValveOn returns true if the valve is currently on.
+func (s *IrrigationStatus) ValveOn(valve int) bool {
+	return s.Valves&(1<<uint(valve)) > 0
+}
+
+func (s *IrrigationStatus) String() string {
+	return fmt.Sprintf("Valves=%08b", s.Valves)
+}
+
+// IrrigationConfig is the rain sensor configuration of an irrigation controller.
+type IrrigationConfig struct {
+	// SensorEnabled indicates the rain sensor input will stop programs from running.
+	SensorEnabled bool
+	// RainDelay is how long programs are held off for after rain is detected.
+	RainDelay time.Duration
+}
+
+func (c *IrrigationConfig) String() string {
+	return fmt.Sprintf("SensorEnabled=%t, RainDelay=%s", c.SensorEnabled, c.RainDelay)
+}
+
+// IrrigationController represents a sprinkler controller such as the Compacta EZRain or EZFlora.
+type IrrigationController struct {
+	*Device
+}
+
+// NewIrrigationController creates a new irrigation controller by raw address.
+func NewIrrigationController(hub Hub, addr Address) (*IrrigationController, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IrrigationController{Device: dev}, nil
+}
+
+// SetValve turns an individual valve on or off.
+func (ic *IrrigationController) SetValve(ctx context.Context, valve int, on bool) error {
+	if valve < 0 || valve >= IrrigationValves {
+		return fmt.Errorf("valve out of range: %d", valve)
+	}
+
+	cmd1 := cmdIrrigationValveOff
+	if on {
+		cmd1 = cmdIrrigationValveOn
+	}
+
+	_, err := ic.sendMessage(ctx, cmd1, byte(valve))
+
+	return err
+}
+
+// StartProgram starts running a stored program.
+func (ic *IrrigationController) StartProgram(ctx context.Context, program int) error {
+	if program < 0 || program >= IrrigationPrograms {
+		return fmt.Errorf("program out of range: %d", program)
+	}
+
+	_, err := ic.sendMessage(ctx, cmdIrrigationProgramOn, byte(program))
+
+	return err
+}
+
+// StopProgram stops a running program.
+func (ic *IrrigationController) StopProgram(ctx context.Context, program int) error {
+	if program < 0 || program >= IrrigationPrograms {
+		return fmt.Errorf("program out of range: %d", program)
+	}
+
+	_, err := ic.sendMessage(ctx, cmdIrrigationProgramOff, byte(program))
+
+	return err
+}
+
+// GetValveStatus reads which valves are currently on.
+func (ic *IrrigationController) GetValveStatus(ctx context.Context) (*IrrigationStatus, error) {
+	if _, err := ic.sendMessage(ctx, cmdIrrigationControl, irrigationControlGetValveStatus); err != nil {
+		return nil, err
+	}
+
+	// The status arrives in a separate direct message after the acknowledgement.
+	for {
+		evt, err := ic.hub.Expect(ctx, &StdCommandResponse{})
+		if err != nil {
+			return nil, err
+		}
+
+		rsp := evt.(*StdCommandResponse)
+		if rsp.From() == ic.address && rsp.Cmd1() == cmdIrrigationValveStatus {
+			return &IrrigationStatus{Valves: rsp.Cmd2()}, nil
+		}
+	}
+}
+
+// GetProgramTimers reads the valve timers of a stored program.
+func (ic *IrrigationController) GetProgramTimers(ctx context.Context, program int) (*IrrigationTimers, error) {
+	if program < 0 || program >= IrrigationPrograms {
+		return nil, fmt.Errorf("program out of range: %d", program)
+	}
+
+	if _, err := ic.sendMessage(ctx, cmdIrrigationTimersRequest, byte(program)); err != nil {
+		return nil, err
+	}
+
+	rsp, err := ic.expectExtended(ctx, cmdIrrigationTimers)
+	if err != nil {
+		return nil, err
+	}
+
+	timers := &IrrigationTimers{}
+
+	for valve := range timers {
+		timers[valve] = time.Duration(rsp.data[valve]) * time.Minute
+	}
+
+	return timers, nil
+}
+
+// SetProgramTimers writes the valve timers of a stored program. Each timer is rounded down to the minute and can be at
+// most 255 minutes.
+func (ic *IrrigationController) SetProgramTimers(ctx context.Context, program int, timers *IrrigationTimers) error {
+	const maxMinutes = 0xFF
+
+	if program < 0 || program >= IrrigationPrograms {
+		return fmt.Errorf("program out of range: %d", program)
+	}
+
+	data := [14]byte{}
+
+	for valve, timer := range timers {
+		minutes := timer / time.Minute
+		if minutes < 0 || minutes > maxMinutes {
+			return fmt.Errorf("timer for valve %d out of range: %s", valve, timer)
+		}
+
+		data[valve] = byte(minutes)
+	}
+
+	_, err := ic.sendExtended(ctx, cmdIrrigationTimers, byte(program), data)
+
+	return err
+}
+
+// GetConfig reads whether the rain sensor is enabled and the current rain delay.
+func (ic *IrrigationController) GetConfig(ctx context.Context) (*IrrigationConfig, error) {
+	data, err := ic.GetExtendedConfig(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IrrigationConfig{
+		SensorEnabled: data[irrigationDataFlags]&irrigationFlagSensorEnabled > 0,
+		RainDelay:     time.Duration(data[irrigationDataRainDelay]) * time.Hour,
+	}, nil
+}
+
+// SetSensorEnabled sets whether the rain sensor input will stop programs from running.
+func (ic *IrrigationController) SetSensorEnabled(ctx context.Context, enabled bool) error {
+	cmd2 := irrigationControlSensorOff
+	if enabled {
+		cmd2 = irrigationControlSensorOn
+	}
+
+	_, err := ic.sendMessage(ctx, cmdIrrigationControl, cmd2)
+
+	return err
+}
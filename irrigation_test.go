@@ -0,0 +1,123 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type IrrigationTestSuite struct {
+	suite.Suite
+	mock       *InsteonHubMock
+	controller *insteon.IrrigationController
+}
+
+func (s *IrrigationTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.controller, err = insteon.NewIrrigationController(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *IrrigationTestSuite) TestSetValve() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x40, 0x03},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x40, 0x03, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x40, 0x03,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x41, 0x03},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x41, 0x03, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x41, 0x03,
+		},
+	)
+
+	s.Require().NoError(s.controller.SetValve(s.mock.ctx, 3, true))
+	s.Require().NoError(s.controller.SetValve(s.mock.ctx, 3, false))
+	s.Require().Error(s.controller.SetValve(s.mock.ctx, insteon.IrrigationValves, true))
+}
+
+func (s *IrrigationTestSuite) TestPrograms() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x42, 0x02},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x42, 0x02, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x42, 0x02,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x43, 0x02},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x43, 0x02, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x43, 0x02,
+		},
+	)
+
+	s.Require().NoError(s.controller.StartProgram(s.mock.ctx, 2))
+	s.Require().NoError(s.controller.StopProgram(s.mock.ctx, 2))
+	s.Require().Error(s.controller.StartProgram(s.mock.ctx, insteon.IrrigationPrograms))
+}
+
+func (s *IrrigationTestSuite) TestGetValveStatus() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x44, 0x02},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x44, 0x02, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x44, 0x02,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x0B, 0x27, 0x05,
+		},
+	)
+
+	status, err := s.controller.GetValveStatus(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().True(status.ValveOn(0))
+	s.Require().False(status.ValveOn(1))
+	s.Require().True(status.ValveOn(2))
+}
+
+func (s *IrrigationTestSuite) TestGetProgramTimers() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x45, 0x01},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x45, 0x01, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x45, 0x01,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x46, 0x01,
+			0x0A, 0x00, 0xFF, 0, 0, 0, 0, 0x05, 0, 0, 0, 0, 0, 0,
+		},
+	)
+
+	timers, err := s.controller.GetProgramTimers(s.mock.ctx, 1)
+	s.Require().NoError(err)
+	s.Require().Equal(10*time.Minute, timers[0])
+	s.Require().Zero(timers[1])
+	s.Require().Equal(255*time.Minute, timers[2])
+	s.Require().Equal(5*time.Minute, timers[7])
+}
+
+func (s *IrrigationTestSuite) TestSetProgramTimers() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x46, 0x01, 0x0A, 0, 0xFF, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xAE},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x46, 0x01, 0x0A, 0, 0xFF, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xAE, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x46, 0x01,
+		},
+	)
+
+	timers := &insteon.IrrigationTimers{10 * time.Minute, 0, 255 * time.Minute}
+	s.Require().NoError(s.controller.SetProgramTimers(s.mock.ctx, 1, timers))
+
+	timers[0] = 256 * time.Minute
+	s.Require().Error(s.controller.SetProgramTimers(s.mock.ctx, 1, timers))
+}
+
+func TestIrrigationSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &IrrigationTestSuite{})
+}
@@ -0,0 +1,125 @@
+package insteon
+
+import "fmt"
+
+// ResponderData is what a device does when the controller of a responder record sends its group a command.
+type ResponderData struct {
+	// OnLevel is the level the device turns on to, devices that can't dim treat anything but zero as full on.
+	OnLevel byte
+	// RampRate is how fast a dimmer gets to the on level, other devices ignore it.
+	RampRate byte
+	// Button is the button or channel of the device that responds, 1 for devices with only one.
+	Button byte
+}
+
+func (d ResponderData) String() string {
+	return fmt.Sprintf("OnLevel=%02X, RampRate=%02X, Button=%d", d.OnLevel, d.RampRate, d.Button)
+}
+
+// Bytes returns the data as it's kept in a record.
+func (d ResponderData) Bytes() [3]byte {
+	return [3]byte{d.OnLevel, d.RampRate, d.Button}
+}
+
+// ControllerData is what the modem keeps in its controller records, the identity of the device it controls. Devices
+// keep something else in theirs, usually the button that controls the group in the last byte.
+type ControllerData struct {
+	Category    Category
+	SubCategory SubCategory
+	Firmware    byte
+}
+
+func (d ControllerData) String() string {
+	return fmt.Sprintf("Category=%02X, SubCategory=%02X, Firmware=%02X", byte(d.Category), byte(d.SubCategory),
+		d.Firmware)
+}
+
+// Bytes returns the data as it's kept in a record.
+func (d ControllerData) Bytes() [3]byte {
+	return [3]byte{byte(d.Category), byte(d.SubCategory), d.Firmware}
+}
+
+// Description returns the product description of the device, or an empty string if it's unknown.
+func (d ControllerData) Description() string {
+	return GetProductDesc(d.Category, d.SubCategory)
+}
+
+// LinkOwner describes who keeps a record, which decides what the record's data means.
+type LinkOwner struct {
+	// Modem is true for the modem's records.
+	Modem bool
+	// Category is the category of the device that keeps the record, CategoryUnassigned if it isn't known.
+	Category Category
+}
+
+// ModemOwner is the owner of the modem's records.
+var ModemOwner = LinkOwner{Modem: true, Category: CategoryUnassigned}
+
+// DeviceOwner returns the owner of the records of a device in category cat, CategoryUnassigned if it isn't known.
+func DeviceOwner(cat Category) LinkOwner {
+	return LinkOwner{Category: cat}
+}
+
+// dims returns true unless the owner is known to be a device that can't dim, which ignores the ramp rate.
+func (o LinkOwner) dims() bool {
+	return o.Category == CategoryUnassigned || o.Category == CategoryDimmableLighting
+}
+
+// ResponderData returns the data of a responder record kept by owner. Only devices keep responder data, false is
+// returned for controller records and the modem's records.
+func (cr *AllLinkRecord) ResponderData(owner LinkOwner) (ResponderData, bool) {
+	if owner.Modem || cr.Flags.Controller() {
+		return ResponderData{}, false
+	}
+
+	return ResponderData{OnLevel: cr.Data[0], RampRate: cr.Data[1], Button: cr.Data[2]}, true
+}
+
+// ControllerData returns the identity of the device kept in a controller record of the modem. Devices keep something
+// else in theirs, false is returned for them and for responder records.
+func (cr *AllLinkRecord) ControllerData(owner LinkOwner) (ControllerData, bool) {
+	if !owner.Modem || !cr.Flags.Controller() {
+		return ControllerData{}, false
+	}
+
+	return ControllerData{Category: Category(cr.Data[0]), SubCategory: SubCategory(cr.Data[1]), Firmware: cr.Data[2]},
+		true
+}
+
+// String describes the record for dumps as if it's kept by a device of an unknown kind, use Format when the owner is
+// known.
+func (cr *AllLinkRecord) String() string {
+	return cr.Format(DeviceOwner(CategoryUnassigned))
+}
+
+// Format describes the record for dumps, spelling out its data the way owner uses it. The modem's controller records
+// show the identity of the device, devices' responder records show what the device does, leaving out the ramp rate for
+// devices that can't dim. Other data is shown as is.
+func (cr *AllLinkRecord) Format(owner LinkOwner) string {
+	if cr.Flags.HighWater() {
+		return "End of Database"
+	}
+
+	state := ""
+	if !cr.Flags.InUse() {
+		state = "Deleted "
+	}
+
+	role := "Responder"
+	if cr.Flags.Controller() {
+		role = "Controller"
+	}
+
+	data := fmt.Sprintf("Data=%02X", cr.Data[:])
+
+	if id, ok := cr.ControllerData(owner); ok {
+		data = id.String()
+	} else if rd, ok := cr.ResponderData(owner); ok {
+		data = rd.String()
+		if !owner.dims() {
+			data = fmt.Sprintf("OnLevel=%02X, Button=%d", rd.OnLevel, rd.Button)
+		}
+	}
+
+	return fmt.Sprintf("%s%s, Group=%d, Address=%s, %s", state, role, cr.Group, cr.Address, data)
+}
@@ -0,0 +1,66 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type LinkDataTestSuite struct {
+	suite.Suite
+}
+
+func (s *LinkDataTestSuite) TestResponderData() {
+	rec := &insteon.AllLinkRecord{Flags: 0xA2, Group: 1, Address: insteon.Address{0x01, 0x02, 0x03},
+		Data: [3]byte{0x7F, 0x1C, 0x02}}
+
+	data, ok := rec.ResponderData(insteon.DeviceOwner(insteon.CategoryDimmableLighting))
+	s.Require().True(ok)
+	s.Require().Equal(insteon.ResponderData{OnLevel: 0x7F, RampRate: 0x1C, Button: 2}, data)
+	s.Require().Equal(rec.Data, data.Bytes())
+	s.Require().Equal("Responder, Group=1, Address=01:02:03, OnLevel=7F, RampRate=1C, Button=2", rec.String())
+
+	// Relays ignore the ramp rate.
+	s.Require().Equal("Responder, Group=1, Address=01:02:03, OnLevel=7F, Button=2",
+		rec.Format(insteon.DeviceOwner(insteon.CategorySwitchedLighting)))
+
+	// The modem doesn't keep responder data.
+	_, ok = rec.ResponderData(insteon.ModemOwner)
+	s.Require().False(ok)
+	s.Require().Equal("Responder, Group=1, Address=01:02:03, Data=7F1C02", rec.Format(insteon.ModemOwner))
+
+	rec.Flags = 0x22
+	s.Require().Equal("Deleted Responder, Group=1, Address=01:02:03, OnLevel=7F, RampRate=1C, Button=2", rec.String())
+}
+
+func (s *LinkDataTestSuite) TestControllerData() {
+	rec := &insteon.AllLinkRecord{Flags: 0xE2, Group: 1, Address: insteon.Address{0xAA, 0xBB, 0xCC},
+		Data: [3]byte{0x01, 0x2E, 0x45}}
+
+	data, ok := rec.ControllerData(insteon.ModemOwner)
+	s.Require().True(ok)
+	s.Require().Equal(insteon.ControllerData{
+		Category:    insteon.CategoryDimmableLighting,
+		SubCategory: 0x2E,
+		Firmware:    0x45,
+	}, data)
+	s.Require().Equal("FanLinc [2475F]", data.Description())
+	s.Require().Equal("Category=01, SubCategory=2E, Firmware=45", data.String())
+	s.Require().Equal(rec.Data, data.Bytes())
+	s.Require().Equal("Controller, Group=1, Address=AA:BB:CC, Category=01, SubCategory=2E, Firmware=45",
+		rec.Format(insteon.ModemOwner))
+
+	// Devices keep something else in their controller records.
+	_, ok = rec.ControllerData(insteon.DeviceOwner(insteon.CategoryDimmableLighting))
+	s.Require().False(ok)
+	s.Require().Equal("Controller, Group=1, Address=AA:BB:CC, Data=012E45", rec.String())
+
+	s.Require().Equal("End of Database", (&insteon.AllLinkRecord{}).String())
+}
+
+func TestLinkDataTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(LinkDataTestSuite))
+}
@@ -0,0 +1,352 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDBVerifyFailed indicates a record written to a device's database didn't read back the same.
+var ErrDBVerifyFailed = errors.New("database record didn't read back as written")
+
+// LinkWrite is a single record written to a device's database when changes are applied.
+type LinkWrite struct {
+	MemAddr uint16
+	// Old is what the record held before, used to roll back. It's nil for records past the end of the database.
+	Old *AllLinkRecord
+	New *AllLinkRecord
+}
+
+func (w *LinkWrite) String() string {
+	return fmt.Sprintf("%04X: %s", w.MemAddr, w.New)
+}
+
+// LinkDatabase is a copy of a device's All-Link database that's changed in batches. Changes are made to the copy
+// first, Plan shows the records that need to be written for them and Apply writes them, reading each one back to make
+// sure it took. If any of them didn't, the records already written are put back the way they were.
+//
+// Deleted records are marked as no longer in use rather than moved around, and their space is reused by later adds.
+type LinkDatabase struct {
+	device   *Device
+	original map[uint16]*AllLinkRecord
+	records  map[uint16]*AllLinkRecord
+	// end is the address of the high water record that ends the database.
+	end uint16
+}
+
+// GetLinkDatabase reads the device's All-Link database so it can be changed.
+func (d *Device) GetLinkDatabase(ctx context.Context) (*LinkDatabase, error) {
+	records, err := d.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &LinkDatabase{device: d, original: records, end: dbFirstRecord}
+	for memAddr := range records {
+		if memAddr <= db.end {
+			db.end = memAddr - dbRecordSize
+		}
+	}
+
+	db.Reset()
+
+	return db, nil
+}
+
+// Records returns the records of the database, including any changes that haven't been applied yet.
+func (db *LinkDatabase) Records() map[uint16]*AllLinkRecord {
+	return copyRecords(db.records)
+}
+
+// Find returns the memory address and record of the entry for addr and group on the controller or responder side,
+// or false if there isn't one.
+func (db *LinkDatabase) Find(addr Address, group byte, controller bool) (uint16, *AllLinkRecord, bool) {
+	memAddr := findAllLinkDBEntry(db.records, addr, group, controller)
+	if memAddr == 0 {
+		return 0, nil, false
+	}
+
+	rec := *db.records[memAddr]
+
+	return memAddr, &rec, true
+}
+
+// Add adds an entry for addr and group, reusing the space of a deleted record if there is one.
+func (db *LinkDatabase) Add(addr Address, group byte, controller bool, data [3]byte) error {
+	if _, _, ok := db.Find(addr, group, controller); ok {
+		return errors.Wrapf(ErrDBEntryAlreadyExists, "address: %s, group: %d, controller: %t", addr, group, controller)
+	}
+
+	memAddr := db.free()
+	db.records[memAddr] = newLinkRecord(addr, group, controller, data)
+
+	return nil
+}
+
+// Update changes the data of the entry for addr and group.
+func (db *LinkDatabase) Update(addr Address, group byte, controller bool, data [3]byte) error {
+	memAddr, rec, ok := db.Find(addr, group, controller)
+	if !ok {
+		return errors.Wrapf(ErrDBEntryNotFound, "address: %s, group: %d, controller: %t", addr, group, controller)
+	}
+
+	rec.Data = data
+	db.records[memAddr] = rec
+
+	return nil
+}
+
+// Delete removes the entry for addr and group.
+func (db *LinkDatabase) Delete(addr Address, group byte, controller bool) error {
+	memAddr, rec, ok := db.Find(addr, group, controller)
+	if !ok {
+		return errors.Wrapf(ErrDBEntryNotFound, "address: %s, group: %d, controller: %t", addr, group, controller)
+	}
+
+	rec.Flags &^= AllLinkRecordFlagsInUse
+	db.records[memAddr] = rec
+
+	return nil
+}
+
+// deleteAt removes the entry at memAddr, for when there's more than one entry for the same address and group.
+func (db *LinkDatabase) deleteAt(memAddr uint16) error {
+	rec, ok := db.records[memAddr]
+	if !ok || !rec.Flags.InUse() {
+		return errors.Wrapf(ErrDBEntryNotFound, "address: %s, record: %04x", db.device.address, memAddr)
+	}
+
+	deleted := *rec
+	deleted.Flags &^= AllLinkRecordFlagsInUse
+	db.records[memAddr] = &deleted
+
+	return nil
+}
+
+// repoint changes the entries for from into entries for to, in place so they keep their flags. Entries that to already
+// has are deleted instead.
+func (db *LinkDatabase) repoint(from, to Address) {
+	for memAddr, rec := range db.records {
+		if !rec.Flags.InUse() || rec.Address != from {
+			continue
+		}
+
+		moved := *rec
+
+		if _, _, ok := db.Find(to, rec.Group, rec.Flags.Controller()); ok {
+			moved.Flags &^= AllLinkRecordFlagsInUse
+		} else {
+			moved.Address = to
+		}
+
+		db.records[memAddr] = &moved
+	}
+}
+
+// Reset throws away the changes that haven't been applied.
+func (db *LinkDatabase) Reset() {
+	db.records = copyRecords(db.original)
+}
+
+// Plan returns the records that need to be written to apply the changes, in the order they're written. Nothing is
+// written, so this also serves as a dry run.
+func (db *LinkDatabase) Plan() []*LinkWrite {
+	var plan []*LinkWrite
+
+	end := db.end
+
+	for memAddr, rec := range db.records {
+		old, ok := db.original[memAddr]
+		if !ok {
+			// Records past the end of the database were never used.
+			old = &AllLinkRecord{}
+		} else if *old == *rec {
+			continue
+		}
+
+		plan = append(plan, &LinkWrite{MemAddr: memAddr, Old: old, New: rec})
+
+		if memAddr <= end {
+			end = memAddr - dbRecordSize
+		}
+	}
+
+	// The database grew, so a new high water record has to end it. Nothing was ever there to roll back to.
+	if end != db.end {
+		plan = append(plan, &LinkWrite{MemAddr: end, New: &AllLinkRecord{}})
+	}
+
+	// Writing from the bottom up puts the new end in place before the records that come before it.
+	sort.Slice(plan, func(i, j int) bool { return plan[i].MemAddr < plan[j].MemAddr })
+
+	return plan
+}
+
+// Apply writes the changes to the device, reading each record back to verify it. If a write fails, the records
+// already written are restored.
+func (db *LinkDatabase) Apply(ctx context.Context) error {
+	plan := db.Plan()
+
+	for idx, write := range plan {
+		if err := db.device.writeRecord(ctx, write.MemAddr, write.New); err != nil {
+			if rbErr := db.rollback(ctx, plan[:idx+1]); rbErr != nil {
+				return errors.Wrapf(rbErr, "unable to roll back after: %s", err)
+			}
+
+			return err
+		}
+	}
+
+	db.original = copyRecords(db.records)
+
+	for _, write := range plan {
+		if write.New.Flags.HighWater() {
+			db.end = write.MemAddr
+		}
+	}
+
+	return nil
+}
+
+// rollback restores the records written by plan, most recent first.
+func (db *LinkDatabase) rollback(ctx context.Context, plan []*LinkWrite) error {
+	for idx := len(plan) - 1; idx >= 0; idx-- {
+		if plan[idx].Old == nil {
+			continue
+		}
+
+		if err := db.device.writeRecord(ctx, plan[idx].MemAddr, plan[idx].Old); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// free returns the address of the first deleted record, or the end of the database if there isn't one.
+func (db *LinkDatabase) free() uint16 {
+	memAddr := db.end
+
+	for addr, rec := range db.records {
+		if !rec.Flags.InUse() && addr > memAddr {
+			memAddr = addr
+		}
+	}
+
+	// Records added since the database was read push the end down.
+	for {
+		if _, ok := db.records[memAddr]; !ok || !db.records[memAddr].Flags.InUse() {
+			return memAddr
+		}
+
+		memAddr -= dbRecordSize
+	}
+}
+
+// writeRecord writes a record of the device's database and reads it back to verify it.
+func (d *Device) writeRecord(ctx context.Context, memAddr uint16, rec *AllLinkRecord) error {
+	version, err := d.GetEngineVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if version == EngineVersionI1 {
+		err = d.pokeRecord(ctx, memAddr, rec)
+	} else {
+		_, err = d.sendExtended(ctx, cmdControlAllLink, 0,
+			d.modifyDbCommand(memAddr, rec.Flags, rec.Group, rec.Address, rec.Data))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	got, err := d.readRecord(ctx, memAddr)
+	if err != nil {
+		return err
+	}
+
+	if *got != *rec {
+		return errors.Wrapf(ErrDBVerifyFailed, "address: %s, record: %04x", d.address, memAddr)
+	}
+
+	return nil
+}
+
+// pokeRecord writes a record straight into the device's memory.
+func (d *Device) pokeRecord(ctx context.Context, memAddr uint16, rec *AllLinkRecord) error {
+	start := memAddr - dbRecordSize + 1
+
+	for offset, value := range rec.toBytes() {
+		if err := d.Poke(ctx, start+uint16(offset), value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readRecord reads a single record of the device's database.
+func (d *Device) readRecord(ctx context.Context, memAddr uint16) (*AllLinkRecord, error) {
+	version, err := d.GetEngineVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &AllLinkRecord{}
+
+	if version == EngineVersionI1 {
+		buf, err := d.PeekBlock(ctx, memAddr-dbRecordSize+1, int(dbRecordSize))
+		if err != nil {
+			return nil, err
+		}
+
+		rec.fromBytes(append([]byte{0, 0}, buf...))
+
+		return rec, nil
+	}
+
+	for attempt := 0; attempt < dbRetries; attempt++ {
+		if err := d.requestRecords(ctx, memAddr, 1); err != nil {
+			return nil, err
+		}
+
+		read := &dbRead{records: make(map[uint16]*AllLinkRecord)}
+		if err := d.receiveRecords(ctx, read, 1, func(int) {}); err != nil {
+			return nil, err
+		}
+
+		if read.end == memAddr {
+			// The record is a high water record.
+			return rec, nil
+		}
+
+		if got, ok := read.records[memAddr]; ok {
+			return got, nil
+		}
+	}
+
+	return nil, errors.Wrapf(ErrDBIncomplete, "address: %s, record: %04x", d.address, memAddr)
+}
+
+func newLinkRecord(addr Address, group byte, controller bool, data [3]byte) *AllLinkRecord {
+	flags := AllLinkRecordFlagsInUse | AllLinkRecordFlagsLast
+	if controller {
+		flags |= AllLinkRecordFlagsContoller
+	}
+
+	return &AllLinkRecord{Flags: flags, Group: group, Address: addr, Data: data}
+}
+
+func copyRecords(records map[uint16]*AllLinkRecord) map[uint16]*AllLinkRecord {
+	cp := make(map[uint16]*AllLinkRecord, len(records))
+
+	for memAddr, rec := range records {
+		r := *rec
+		cp[memAddr] = &r
+	}
+
+	return cp
+}
@@ -0,0 +1,102 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type LinkDatabaseTestSuite struct {
+	suite.Suite
+	mock   *InsteonHubMock
+	device *insteon.Device
+}
+
+func (s *LinkDatabaseTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.device, err = insteon.NewDevice(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+
+	// A controller record for the modem, then a deleted record.
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(insteon.Address{0xAA, 0xBB, 0xCC},
+		[]byte{0x0F, 0xFF, 0xE2, 0x01, 0x01, 0x02, 0x03, 0x03, 0x1F, 0x01},
+		[]byte{0x0F, 0xF7, 0x22, 0x05, 0x44, 0x55, 0x66, 0x00, 0x00, 0x00},
+	)
+}
+
+func (s *LinkDatabaseTestSuite) TestPlan() {
+	db, err := s.device.GetLinkDatabase(s.mock.ctx)
+	s.Require().NoError(err)
+
+	s.Require().NoError(db.Add(insteon.Address{0x11, 0x22, 0x33}, 1, false, [3]byte{0xFF, 0x1F, 0x01}))
+	s.Require().NoError(db.Add(insteon.Address{0x44, 0x55, 0x66}, 2, true, [3]byte{}))
+	s.Require().NoError(db.Delete(insteon.Address{0x01, 0x02, 0x03}, 1, true))
+	s.Require().ErrorIs(db.Delete(insteon.Address{0x01, 0x02, 0x03}, 1, true), insteon.ErrDBEntryNotFound)
+	s.Require().ErrorIs(db.Add(insteon.Address{0x11, 0x22, 0x33}, 1, false, [3]byte{}), insteon.ErrDBEntryAlreadyExists)
+
+	// The deleted record is reused first, then the database grows and gets a new end.
+	plan := db.Plan()
+	s.Require().Len(plan, 4)
+	s.Require().Equal(uint16(0x0FE7), plan[0].MemAddr)
+	s.Require().True(plan[0].New.Flags.HighWater())
+	s.Require().Nil(plan[0].Old)
+	s.Require().Equal(uint16(0x0FEF), plan[1].MemAddr)
+	s.Require().Equal(insteon.Address{0x44, 0x55, 0x66}, plan[1].New.Address)
+	s.Require().Equal(uint16(0x0FF7), plan[2].MemAddr)
+	s.Require().Equal(insteon.Address{0x11, 0x22, 0x33}, plan[2].New.Address)
+	s.Require().Equal(uint16(0x0FFF), plan[3].MemAddr)
+	s.Require().False(plan[3].New.Flags.InUse())
+
+	// Planning is a dry run.
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+
+	db.Reset()
+	s.Require().Empty(db.Plan())
+}
+
+func (s *LinkDatabaseTestSuite) TestApply() {
+	db, err := s.device.GetLinkDatabase(s.mock.ctx)
+	s.Require().NoError(err)
+
+	s.Require().NoError(db.Update(insteon.Address{0x01, 0x02, 0x03}, 1, true, [3]byte{0x03, 0x1F, 0x02}))
+
+	rec := []byte{0x0F, 0xFF, 0xE2, 0x01, 0x01, 0x02, 0x03, 0x03, 0x1F, 0x02}
+	s.mock.ExpectRecordWrite(insteon.Address{0xAA, 0xBB, 0xCC}, rec, rec)
+
+	s.Require().NoError(db.Apply(s.mock.ctx))
+	s.Require().Empty(db.Plan())
+}
+
+func (s *LinkDatabaseTestSuite) TestRollback() {
+	db, err := s.device.GetLinkDatabase(s.mock.ctx)
+	s.Require().NoError(err)
+
+	s.Require().NoError(db.Add(insteon.Address{0x11, 0x22, 0x33}, 1, false, [3]byte{0xFF, 0x1F, 0x01}))
+	s.Require().NoError(db.Add(insteon.Address{0x44, 0x55, 0x66}, 2, true, [3]byte{}))
+
+	addr := insteon.Address{0xAA, 0xBB, 0xCC}
+	end := []byte{0x0F, 0xE7, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	added := []byte{0x0F, 0xEF, 0xC2, 0x02, 0x44, 0x55, 0x66, 0x00, 0x00, 0x00}
+	restored := []byte{0x0F, 0xEF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	// The new record reads back wrong, so it's put back to being the end of the database. The reused record was never
+	// written.
+	s.mock.ExpectRecordWrite(addr, end, end)
+	s.mock.ExpectRecordWrite(addr, added, restored)
+	s.mock.ExpectRecordWrite(addr, restored, restored)
+
+	s.Require().ErrorIs(db.Apply(s.mock.ctx), insteon.ErrDBVerifyFailed)
+}
+
+func TestLinkDatabaseSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &LinkDatabaseTestSuite{})
+}
@@ -0,0 +1,276 @@
+package insteon
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultResponderData is the data of responder records whose data isn't known, full on at the default ramp rate for
+// the first button.
+var defaultResponderData = ResponderData{OnLevel: 0xFF, RampRate: 0x1C, Button: 1}.Bytes()
+
+// LinkManager links devices, and the modem, to each other without anyone having to press their SET buttons. Both
+// halves of a link are written directly: the controller's record of the responder and the responder's record of the
+// controller.
+type LinkManager struct {
+	hub Hub
+
+	mu      sync.Mutex
+	modem   *Address
+	modemDB *ModemLinkDB
+	devices map[Address]*Device
+	// ids holds what devices identified themselves as, which the modem keeps in its controller records.
+	ids map[Address]ControllerData
+}
+
+// NewLinkManager creates a new link manager.
+func NewLinkManager(hub Hub) *LinkManager {
+	return &LinkManager{hub: hub, devices: make(map[Address]*Device), ids: make(map[Address]ControllerData)}
+}
+
+// Link links controller to responder for group, either of which may be the modem. The responder responds with
+// responderData, for most devices the on level, ramp rate and button. Records that are already there are left alone
+// or updated, so linking again is harmless.
+func (m *LinkManager) Link(ctx context.Context, controller, responder Address, group byte,
+	responderData [3]byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	modem, err := m.modemAddress(ctx)
+	if err != nil {
+		return err
+	}
+
+	// This can mean asking the responder who it is, which is done before anything is written.
+	data, err := m.controllerData(ctx, controller == modem, responder, group)
+	if err != nil {
+		return err
+	}
+
+	// The responder goes first so the controller never sends to a device that ignores it.
+	added, err := m.ensure(ctx, responder, controller, group, false, responderData)
+	if err != nil {
+		return err
+	}
+
+	if _, err := m.ensure(ctx, controller, responder, group, true, data); err != nil {
+		if added {
+			// Don't leave half a link behind, this is best effort as something has already gone wrong.
+			_ = m.remove(ctx, responder, controller, group, false)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Unlink removes both halves of the link between controller and responder for group. Halves that are already gone
+// are ignored.
+func (m *LinkManager) Unlink(ctx context.Context, controller, responder Address, group byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.remove(ctx, controller, responder, group, true); err != nil {
+		return err
+	}
+
+	return m.remove(ctx, responder, controller, group, false)
+}
+
+// Device returns the device at addr, sharing what's been learned about it across links.
+func (m *LinkManager) Device(addr Address) *Device {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.device(addr)
+}
+
+// modemState returns the modem's address and the records in its database.
+func (m *LinkManager) modemState(ctx context.Context) (Address, []*AllLinkRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	modem, err := m.modemAddress(ctx)
+	if err != nil {
+		return Address{}, nil, err
+	}
+
+	db, err := m.modemLinkDB(ctx)
+	if err != nil {
+		return Address{}, nil, err
+	}
+
+	return modem, db.Records(), nil
+}
+
+// controllerData returns the data of a controller record of responder for group. The modem keeps the identity of the
+// responder, which is asked for once, devices keep the button that controls the group.
+func (m *LinkManager) controllerData(ctx context.Context, modem bool, responder Address, group byte) ([3]byte, error) {
+	if !modem {
+		return deviceControllerData(group), nil
+	}
+
+	data, ok := m.ids[responder]
+	if !ok {
+		id, err := m.device(responder).Identify(ctx)
+		if err != nil {
+			return [3]byte{}, err
+		}
+
+		data = ControllerData{Category: Category(id.Category), SubCategory: SubCategory(id.SubCategory),
+			Firmware: id.Firmware}
+		m.ids[responder] = data
+	}
+
+	return data.Bytes(), nil
+}
+
+// deviceControllerData returns the data of a device's controller record for group, the button that controls the group
+// in the last byte.
+func deviceControllerData(group byte) [3]byte {
+	return [3]byte{0, 0, group}
+}
+
+func (m *LinkManager) device(addr Address) *Device {
+	dev, ok := m.devices[addr]
+	if !ok {
+		dev, _ = NewDevice(m.hub, addr)
+		m.devices[addr] = dev
+	}
+
+	return dev
+}
+
+// modemAddress returns the modem's address, which is only looked up once.
+func (m *LinkManager) modemAddress(ctx context.Context) (Address, error) {
+	if m.modem == nil {
+		info, err := m.hub.GetInfo(ctx)
+		if err != nil {
+			return Address{}, err
+		}
+
+		m.modem = &info.Address
+	}
+
+	return *m.modem, nil
+}
+
+// isModem returns true if addr is the modem's address.
+func (m *LinkManager) isModem(ctx context.Context, addr Address) (bool, error) {
+	modem, err := m.modemAddress(ctx)
+
+	return modem == addr, err
+}
+
+// modemLinkDB returns the modem's database, which is only read once and then kept up to date as it's changed.
+func (m *LinkManager) modemLinkDB(ctx context.Context) (*ModemLinkDB, error) {
+	if m.modemDB == nil {
+		db := NewModemLinkDB(m.hub)
+		if err := db.Load(ctx); err != nil {
+			return nil, err
+		}
+
+		m.modemDB = db
+	}
+
+	return m.modemDB, nil
+}
+
+// ensure makes sure owner's database has a record for peer and group with data, returning true if it was added.
+func (m *LinkManager) ensure(ctx context.Context, owner, peer Address, group byte, controller bool,
+	data [3]byte) (bool, error) {
+	modem, err := m.isModem(ctx, owner)
+	if err != nil {
+		return false, err
+	}
+
+	role := LinkCodeResponder
+	if controller {
+		role = LinkCodeController
+	}
+
+	if modem {
+		db, err := m.modemLinkDB(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		if rec, ok := db.Find(peer, group, role); ok {
+			if rec.Data == data {
+				return false, nil
+			}
+
+			return false, db.Update(ctx, peer, group, role, data)
+		}
+
+		if controller {
+			return true, db.AddController(ctx, peer, group, data)
+		}
+
+		return true, db.AddResponder(ctx, peer, group, data)
+	}
+
+	db, err := m.device(owner).GetLinkDatabase(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	_, rec, ok := db.Find(peer, group, controller)
+
+	switch {
+	case !ok:
+		err = db.Add(peer, group, controller, data)
+	case rec.Data != data:
+		err = db.Update(peer, group, controller, data)
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return !ok, db.Apply(ctx)
+}
+
+// remove removes owner's record for peer and group, if there is one.
+func (m *LinkManager) remove(ctx context.Context, owner, peer Address, group byte, controller bool) error {
+	modem, err := m.isModem(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	role := LinkCodeResponder
+	if controller {
+		role = LinkCodeController
+	}
+
+	if modem {
+		db, err := m.modemLinkDB(ctx)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := db.Find(peer, group, role); !ok {
+			return nil
+		}
+
+		return db.Delete(ctx, peer, group, role)
+	}
+
+	db, err := m.device(owner).GetLinkDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, _, ok := db.Find(peer, group, controller); !ok {
+		return nil
+	}
+
+	if err := db.Delete(peer, group, controller); err != nil {
+		return err
+	}
+
+	return db.Apply(ctx)
+}
@@ -0,0 +1,80 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type LinkManagerTestSuite struct {
+	suite.Suite
+	mock    *InsteonHubMock
+	manager *insteon.LinkManager
+}
+
+func (s *LinkManagerTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.manager = insteon.NewLinkManager(hub)
+}
+
+func (s *LinkManagerTestSuite) TestLinkUnlink() {
+	modem, device := insteon.Address{0x01, 0x02, 0x03}, insteon.Address{0xAA, 0xBB, 0xCC}
+	linked := []byte{0x0F, 0xFF, 0x82, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1F, 0x01}
+	end := []byte{0x0F, 0xF7, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+		// The modem keeps what the device identifies as in its controller record.
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x10, 0x00,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45, 0x8B, 0x01, 0x00,
+		},
+	)
+	// The device gets the responder half.
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device)
+	s.mock.ExpectRecordWrite(device, end, end)
+	s.mock.ExpectRecordWrite(device, linked, linked)
+	// Then the modem gets the controller half.
+	s.mock.Expect(
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x6F, 0x40, 0xC2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45},
+		[]byte{0x02, 0x6F, 0x40, 0xC2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45, 0x06},
+	)
+
+	s.Require().NoError(s.manager.Link(s.mock.ctx, modem, device, 1, [3]byte{0xFF, 0x1F, 0x01}))
+
+	// Linking again finds both halves already there.
+	s.mock.ExpectDatabase(device, linked)
+
+	s.Require().NoError(s.manager.Link(s.mock.ctx, modem, device, 1, [3]byte{0xFF, 0x1F, 0x01}))
+
+	// Unlinking removes the controller half first.
+	unlinked := []byte{0x0F, 0xFF, 0x02, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1F, 0x01}
+
+	s.mock.Expect(
+		[]byte{0x02, 0x6F, 0x80, 0x42, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45},
+		[]byte{0x02, 0x6F, 0x80, 0x42, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45, 0x06},
+	)
+	s.mock.ExpectDatabase(device, linked)
+	s.mock.ExpectRecordWrite(device, unlinked, unlinked)
+
+	s.Require().NoError(s.manager.Unlink(s.mock.ctx, modem, device, 1))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func TestLinkManagerSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &LinkManagerTestSuite{})
+}
@@ -0,0 +1,213 @@
+package insteon
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrModemReset indicates the modem was reset by its user, which clears its database, during a linking session.
+var ErrModemReset = errors.New("modem was reset")
+
+// linkCancelTimeout is how long to wait for the modem to leave All-Link mode at the end of a linking session.
+const linkCancelTimeout = 2 * time.Second
+
+// LinkSessionEventType describes what happened during a linking session.
+type LinkSessionEventType int
+
+const (
+	// LinkSessionWaiting is generated each time the modem enters All-Link mode and waits for a device.
+	LinkSessionWaiting LinkSessionEventType = iota
+	// LinkSessionLinked is generated when a device has been linked.
+	LinkSessionLinked
+	// LinkSessionUnlinked is generated when a device has been unlinked instead.
+	LinkSessionUnlinked
+	// LinkSessionButton is generated when the modem's SET button is used.
+	LinkSessionButton
+	// LinkSessionTimeout is generated when no device was linked in time, which ends the session.
+	LinkSessionTimeout
+)
+
+func (t LinkSessionEventType) String() string {
+	switch t {
+	case LinkSessionWaiting:
+		return "Waiting"
+	case LinkSessionLinked:
+		return "Linked"
+	case LinkSessionUnlinked:
+		return "Unlinked"
+	case LinkSessionButton:
+		return "Button"
+	case LinkSessionTimeout:
+		return "Timeout"
+	default:
+		return "Unknown"
+	}
+}
+
+// LinkSessionEvent reports the progress of a linking session.
+type LinkSessionEvent struct {
+	Type LinkSessionEventType
+	// Link is the link that completed, for LinkSessionLinked and LinkSessionUnlinked.
+	Link *AllLinkCompleted
+	// Description is the product description of the device, or an empty string if it's unknown.
+	Description string
+	// Name is the name the device was given, if any.
+	Name string
+	// Err is why the device couldn't be given its name.
+	Err error
+	// Button is how the modem's SET button was used, for LinkSessionButton.
+	Button ButtonEventType
+}
+
+// LinkSessionListener is notified of the progress of a linking session.
+type LinkSessionListener func(evt *LinkSessionEvent)
+
+// LinkSession puts the modem in All-Link mode and links one device after another as their SET buttons are pressed,
+// until no device turns up in time.
+type LinkSession struct {
+	hub Hub
+	// Code is the modem's role in the links, LinkCodeAuto unless changed.
+	Code  LinkCode
+	Group byte
+	// Timeout is how long to wait for each device.
+	Timeout time.Duration
+	// Devices is the number of devices to link before the session ends, zero to keep going until one doesn't turn up.
+	Devices int
+	// Namer, if not nil, is asked for the name of each device linked, along with its product description. The device
+	// is given the name unless it's empty.
+	Namer func(link *AllLinkCompleted, description string) string
+
+	mu       sync.Mutex
+	listener LinkSessionListener
+	cancel   context.CancelFunc
+	reset    bool
+}
+
+// NewLinkSession creates a linking session for group that waits up to timeout for each device.
+func NewLinkSession(hub Hub, group byte, timeout time.Duration) *LinkSession {
+	return &LinkSession{hub: hub, Code: LinkCodeAuto, Group: group, Timeout: timeout}
+}
+
+// Run runs the session, reporting its progress to listener if not nil, and returns the links that completed. Running
+// out of time ends the session normally, a reset of the modem ends it with ErrModemReset.
+func (s *LinkSession) Run(ctx context.Context, listener LinkSessionListener) ([]*AllLinkCompleted, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.start(listener, cancel)
+	defer s.start(nil, nil)
+
+	// The hub is only watched while the session runs, a method value is made once so it can be removed again.
+	watch := s.watch
+	s.hub.AddEventListener(watch)
+
+	defer s.hub.RemoveEventListener(watch)
+
+	var linked []*AllLinkCompleted
+
+	for s.Devices == 0 || len(linked) < s.Devices {
+		s.emit(&LinkSessionEvent{Type: LinkSessionWaiting})
+
+		linkCtx, linkCancel := context.WithTimeout(ctx, s.Timeout)
+		done, err := s.hub.StartAllLink(linkCtx, s.Code, s.Group)
+
+		linkCancel()
+
+		if err != nil {
+			return linked, s.stop(ctx, err)
+		}
+
+		evt := &LinkSessionEvent{
+			Type:        LinkSessionLinked,
+			Link:        done,
+			Description: GetProductDesc(done.Category, done.SubCategory),
+		}
+
+		if done.LinkCode == LinkCodeDeleted {
+			evt.Type = LinkSessionUnlinked
+			s.emit(evt)
+
+			continue
+		}
+
+		if s.Namer != nil {
+			if evt.Name = s.Namer(done, evt.Description); evt.Name != "" {
+				dev, _ := NewDevice(s.hub, done.Address)
+				evt.Err = dev.SetName(ctx, evt.Name)
+			}
+		}
+
+		linked = append(linked, done)
+		s.emit(evt)
+	}
+
+	return linked, nil
+}
+
+// stop works out how a session ended after StartAllLink failed with err, taking the modem out of All-Link mode if
+// it's still in it.
+func (s *LinkSession) stop(ctx context.Context, err error) error {
+	s.mu.Lock()
+	reset := s.reset
+	s.mu.Unlock()
+
+	if reset {
+		return ErrModemReset
+	}
+
+	// The modem stays in All-Link mode until it's told otherwise, even when ctx is done.
+	cancelCtx, cancel := context.WithTimeout(context.Background(), linkCancelTimeout)
+	defer cancel()
+
+	cancelErr := s.hub.CancelAllLink(cancelCtx)
+
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case errors.Cause(err) != context.DeadlineExceeded:
+		return err
+	}
+
+	s.emit(&LinkSessionEvent{Type: LinkSessionTimeout})
+
+	return cancelErr
+}
+
+// start sets up the session to report to listener and be cancelled by cancel, or with nils, that it's over.
+func (s *LinkSession) start(listener LinkSessionListener, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.listener, s.cancel, s.reset = listener, cancel, false
+}
+
+// watch handles the modem's own events while the session runs.
+func (s *LinkSession) watch(evt Event, err error) {
+	switch e := evt.(type) {
+	case *ButtonEvent:
+		s.emit(&LinkSessionEvent{Type: LinkSessionButton, Button: e.Event})
+	case *UserReset:
+		s.mu.Lock()
+		cancel := s.cancel
+		s.reset = cancel != nil
+		s.mu.Unlock()
+
+		if cancel != nil {
+			cancel()
+		}
+	}
+}
+
+// emit reports evt to the listener of the session that's running, if there is one. Events from the hub and from Run
+// are reported one at a time.
+func (s *LinkSession) emit(evt *LinkSessionEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.listener != nil {
+		s.listener(evt)
+	}
+}
@@ -0,0 +1,95 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type LinkSessionTestSuite struct {
+	suite.Suite
+	mock    *InsteonHubMock
+	session *insteon.LinkSession
+}
+
+func (s *LinkSessionTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.session = insteon.NewLinkSession(hub, 1, 500*time.Millisecond)
+}
+
+func (s *LinkSessionTestSuite) TestRun() {
+	device := insteon.Address{0xAA, 0xBB, 0xCC}
+	name := []byte{'P', 'o', 'r', 'c', 'h'}
+
+	// A FanLinc links and is named, then the modem's SET button is tapped and nothing else turns up.
+	s.mock.Expect(
+		[]byte{0x02, 0x64, 0x03, 0x01},
+		[]byte{0x02, 0x64, 0x03, 0x01, 0x06},
+		nil,
+		[]byte{0x02, 0x53, 0x01, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45},
+	)
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectExtended(device, 0x03, 0x02, name)
+	s.mock.Expect(
+		[]byte{0x02, 0x64, 0x03, 0x01},
+		[]byte{0x02, 0x64, 0x03, 0x01, 0x06, 0x02, 0x54, 0x02},
+		[]byte{0x02, 0x65},
+		[]byte{0x02, 0x65, 0x06},
+	)
+
+	s.session.Namer = func(link *insteon.AllLinkCompleted, description string) string {
+		s.Require().Equal("FanLinc [2475F]", description)
+
+		return "Porch"
+	}
+
+	var events []*insteon.LinkSessionEvent
+
+	linked, err := s.session.Run(s.mock.ctx, func(evt *insteon.LinkSessionEvent) { events = append(events, evt) })
+	s.Require().NoError(err)
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+
+	link := &insteon.AllLinkCompleted{
+		LinkCode:    insteon.LinkCodeController,
+		Group:       1,
+		Address:     device,
+		Category:    insteon.CategoryDimmableLighting,
+		SubCategory: 0x2E,
+		Firmware:    0x45,
+	}
+
+	s.Require().Equal([]*insteon.AllLinkCompleted{link}, linked)
+	s.Require().Len(events, 5)
+	s.Require().Equal([]*insteon.LinkSessionEvent{
+		{Type: insteon.LinkSessionWaiting},
+		{Type: insteon.LinkSessionLinked, Link: link, Description: "FanLinc [2475F]", Name: "Porch"},
+		{Type: insteon.LinkSessionWaiting},
+	}, events[:3])
+
+	// The button is reported as it comes in from the hub, which isn't tied to when Run reports the timeout.
+	s.Require().ElementsMatch([]*insteon.LinkSessionEvent{
+		{Type: insteon.LinkSessionButton, Button: insteon.ButtonEventSetTapped},
+		{Type: insteon.LinkSessionTimeout},
+	}, events[3:])
+}
+
+func (s *LinkSessionTestSuite) TestRunReset() {
+	s.mock.Expect(
+		[]byte{0x02, 0x64, 0x03, 0x01},
+		[]byte{0x02, 0x64, 0x03, 0x01, 0x06, 0x02, 0x55},
+	)
+
+	linked, err := s.session.Run(s.mock.ctx, nil)
+	s.Require().ErrorIs(err, insteon.ErrModemReset)
+	s.Require().Empty(linked)
+}
+
+func TestLinkSessionTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(LinkSessionTestSuite))
+}
@@ -0,0 +1,221 @@
+package insteon
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// lockGroup is the group a lock controller is operated and reports on.
+const lockGroup byte = 1
+
+// LockEvent is generated when a lock reports being locked or unlocked.
+type LockEvent struct {
+	deviceEvent
+	Locked bool
+}
+
+// ErrNotLinked indicates the modem hasn't been confirmed as a controller of the lock, see Lock.Linked and Lock.Link.
+var ErrNotLinked = errors.New("lock isn't linked to the modem")
+
+// Lock represents a door lock controller such as the MorningLinc [2458A1]. The MorningLinc ignores commands from a
+// modem unless the modem is linked to it as a controller, so commands are refused until the link has been confirmed
+// with Linked or created with Link.
+type Lock struct {
+	*Device
+	events deviceEvents
+
+	mu     sync.Mutex
+	linked bool
+}
+
+// NewLock creates a new lock controller by raw address.
+func NewLock(hub Hub, addr Address) (*Lock, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Lock{Device: dev}, nil
+}
+
+// Linked checks whether the modem is linked to the lock as a controller, with a responder entry on the lock and a
+// controller entry on the modem. Once the link has been confirmed it isn't checked again.
+func (l *Lock) Linked(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.linked {
+		return true, nil
+	}
+
+	_, onLock, onModem, err := l.linkState(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	l.linked = onLock && onModem
+
+	return l.linked, nil
+}
+
+// Link links the modem to the lock as a controller, creating the responder entry on the lock and the controller entry
+// on the modem if either is missing.
+func (l *Lock) Link(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	modem, onLock, onModem, err := l.linkState(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !onLock {
+		if err := l.AddAllLink(ctx, modem, lockGroup, [3]byte{0xFF, 0x1F, lockGroup}, false); err != nil {
+			return err
+		}
+	}
+
+	if !onModem {
+		// The modem keeps what the lock identifies as in its controller record.
+		id, err := l.Identify(ctx)
+		if err != nil {
+			return err
+		}
+
+		data := ControllerData{Category: Category(id.Category), SubCategory: SubCategory(id.SubCategory),
+			Firmware: id.Firmware}
+		if err := l.hub.ModifyAllLinkEntry(ctx, ManageAllLinkAddController,
+			AllLinkRecordFlagsInUse|AllLinkRecordFlagsContoller, lockGroup, l.address, data.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	l.linked = true
+
+	return nil
+}
+
+// linkState returns the modem's address and whether the lock and modem each have their half of the link.
+func (l *Lock) linkState(ctx context.Context) (Address, bool, bool, error) {
+	info, err := l.hub.GetInfo(ctx)
+	if err != nil {
+		return Address{}, false, false, err
+	}
+
+	db, err := l.GetDatabase(ctx)
+	if err != nil {
+		return Address{}, false, false, err
+	}
+
+	memAddr := findAllLinkDBEntry(db, info.Address, lockGroup, false)
+
+	records, err := l.hub.GetAllLinkDatabase(ctx)
+	if err != nil {
+		return Address{}, false, false, err
+	}
+
+	onModem := false
+
+	for _, rec := range records {
+		if rec.Flags.Controller() && rec.Address == l.address && rec.Group == lockGroup {
+			onModem = true
+		}
+	}
+
+	return info.Address, memAddr != 0, onModem, nil
+}
+
+// Lock locks the door.
+func (l *Lock) Lock(ctx context.Context) error {
+	if err := l.checkLinked(); err != nil {
+		return err
+	}
+
+	_, err := l.sendMessage(ctx, cmdControlOn, 0xFF)
+
+	return err
+}
+
+// Unlock unlocks the door.
+func (l *Lock) Unlock(ctx context.Context) error {
+	if err := l.checkLinked(); err != nil {
+		return err
+	}
+
+	_, err := l.sendMessage(ctx, cmdControlOff, 0)
+
+	return err
+}
+
+// TurnOn locks the door, the same as Lock. It shadows the device's own so the link check can't be bypassed.
+func (l *Lock) TurnOn(ctx context.Context) error {
+	return l.Lock(ctx)
+}
+
+// TurnOnRamp locks the door, the same as Lock, the lock has no ramp.
+func (l *Lock) TurnOnRamp(ctx context.Context, ramp bool) error {
+	return l.Lock(ctx)
+}
+
+// TurnOnLevel locks the door, the same as Lock, the lock has no levels.
+func (l *Lock) TurnOnLevel(ctx context.Context, ramp bool, level byte) error {
+	return l.Lock(ctx)
+}
+
+// TurnOff unlocks the door, the same as Unlock. It shadows the device's own so the link check can't be bypassed.
+func (l *Lock) TurnOff(ctx context.Context) error {
+	return l.Unlock(ctx)
+}
+
+// TurnOffRamp unlocks the door, the same as Unlock, the lock has no ramp.
+func (l *Lock) TurnOffRamp(ctx context.Context, ramp bool) error {
+	return l.Unlock(ctx)
+}
+
+func (l *Lock) checkLinked() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.linked {
+		return errors.Wrapf(ErrNotLinked, "address: %s", l.address)
+	}
+
+	return nil
+}
+
+// IsLocked returns true if the door is currently locked.
+func (l *Lock) IsLocked(ctx context.Context) (bool, error) {
+	status, err := l.GetStatus(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return status.Level > 0, nil
+}
+
+// AddListener registers a listener that's notified with a LockEvent whenever the lock is locked or unlocked.
+func (l *Lock) AddListener(listener DeviceEventListener) {
+	l.events.add(listener)
+	l.events.watch(l.hub, l.address, l.handleMessage)
+}
+
+func (l *Lock) handleMessage(rsp CommandResponse) {
+	if group, ok := groupBroadcast(rsp); !ok || group != lockGroup {
+		return
+	}
+
+	evt := &LockEvent{deviceEvent: deviceEvent{from: l.address}}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn, cmdControlFastOn:
+		evt.Locked = true
+	case cmdControlOff, cmdControlFastOff:
+		evt.Locked = false
+	default:
+		return
+	}
+
+	l.events.emit(evt)
+}
@@ -0,0 +1,126 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type LockTestSuite struct {
+	suite.Suite
+	mock *InsteonHubMock
+	lock *insteon.Lock
+}
+
+func (s *LockTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.lock, err = insteon.NewLock(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+// expectLinked scripts the modem and lock link tables both holding their half of the link.
+func (s *LockTestSuite) expectLinked() {
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+	)
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2F, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xCF},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2F, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xCF, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2F, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00,
+			0x00, 0x01, 0x0F, 0xFF, 0x00, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1F, 0x01, 0x00,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2F, 0x00,
+			0x00, 0x01, 0x0F, 0xF7, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x69},
+		[]byte{0x02, 0x69, 0x06, 0x02, 0x57, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x0F, 0x06, 0x41},
+		[]byte{0x02, 0x6a},
+		[]byte{0x02, 0x6a, 0x15},
+	)
+}
+
+func (s *LockTestSuite) TestNotLinked() {
+	s.Require().ErrorIs(s.lock.Lock(s.mock.ctx), insteon.ErrNotLinked)
+	s.Require().ErrorIs(s.lock.Unlock(s.mock.ctx), insteon.ErrNotLinked)
+
+	// The device's own on and off commands are guarded the same way.
+	s.Require().ErrorIs(s.lock.TurnOn(s.mock.ctx), insteon.ErrNotLinked)
+	s.Require().ErrorIs(s.lock.TurnOnRamp(s.mock.ctx, true), insteon.ErrNotLinked)
+	s.Require().ErrorIs(s.lock.TurnOnLevel(s.mock.ctx, false, 0x80), insteon.ErrNotLinked)
+	s.Require().ErrorIs(s.lock.TurnOff(s.mock.ctx), insteon.ErrNotLinked)
+	s.Require().ErrorIs(s.lock.TurnOffRamp(s.mock.ctx, true), insteon.ErrNotLinked)
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *LockTestSuite) TestLockUnlock() {
+	s.expectLinked()
+
+	linked, err := s.lock.Linked(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().True(linked)
+
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x11, 0xFF},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x11, 0xFF, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x11, 0xFF,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x13, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x13, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x13, 0x00,
+		},
+	)
+
+	s.Require().NoError(s.lock.Lock(s.mock.ctx))
+	s.Require().NoError(s.lock.Unlock(s.mock.ctx))
+}
+
+func (s *LockTestSuite) TestIsLocked() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x00, 0xFF,
+		},
+	)
+
+	locked, err := s.lock.IsLocked(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().True(locked)
+}
+
+func (s *LockTestSuite) TestEvents() {
+	events := make(chan *insteon.LockEvent, 1)
+
+	s.lock.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.LockEvent)
+	})
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x01, 0xCF, 0x13, 0x00})
+	}()
+
+	select {
+	case evt := <-events:
+		s.Require().False(evt.Locked)
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+}
+
+func TestLockSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &LockTestSuite{})
+}
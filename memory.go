@@ -0,0 +1,80 @@
+package insteon
+
+import (
+	"context"
+)
+
+// Peek reads a byte of the device's memory. Older devices keep their All-Link database and configuration in memory
+// that can only be reached this way.
+func (d *Device) Peek(ctx context.Context, memAddr uint16) (byte, error) {
+	d.memMu.Lock()
+	defer d.memMu.Unlock()
+
+	return d.peek(ctx, memAddr)
+}
+
+// PeekBlock reads length bytes of the device's memory starting at memAddr.
+func (d *Device) PeekBlock(ctx context.Context, memAddr uint16, length int) ([]byte, error) {
+	d.memMu.Lock()
+	defer d.memMu.Unlock()
+
+	buf := make([]byte, length)
+
+	for idx := range buf {
+		value, err := d.peek(ctx, memAddr+uint16(idx))
+		if err != nil {
+			return nil, err
+		}
+
+		buf[idx] = value
+	}
+
+	return buf, nil
+}
+
+// Poke writes a byte of the device's memory. Writing to the wrong address can leave the device unusable until it's
+// factory reset.
+func (d *Device) Poke(ctx context.Context, memAddr uint16, value byte) error {
+	d.memMu.Lock()
+	defer d.memMu.Unlock()
+
+	// Peeking selects the address the poke writes to.
+	if _, err := d.peek(ctx, memAddr); err != nil {
+		return err
+	}
+
+	if _, err := d.sendMessage(ctx, cmdControlPoke, value); err != nil {
+		d.msb = nil
+
+		return err
+	}
+
+	return nil
+}
+
+// peek reads a byte of the device's memory, only changing the MSB of the device's address register when it differs
+// from the one last set. The caller must hold memMu.
+func (d *Device) peek(ctx context.Context, memAddr uint16) (byte, error) {
+	msb := byte(memAddr >> 8)
+
+	if d.msb == nil || *d.msb != msb {
+		if _, err := d.sendMessage(ctx, cmdControlSetMSB, msb); err != nil {
+			d.msb = nil
+
+			return 0, err
+		}
+
+		d.msb = &msb
+	}
+
+	rsp, err := d.sendMessage(ctx, cmdControlPeek, byte(memAddr))
+	if err != nil {
+		// Whether the device saw the MSB is no longer certain.
+		d.msb = nil
+
+		return 0, err
+	}
+
+	// The value comes back in the acknowledgement.
+	return rsp.Cmd2(), nil
+}
@@ -0,0 +1,195 @@
+package insteon
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrMigrationIncomplete indicates some devices couldn't be migrated, running the migration again retries them.
+var ErrMigrationIncomplete = errors.New("migration incomplete")
+
+// MigrationStep is reported as each step of a migration finishes, whether or not it succeeded.
+type MigrationStep struct {
+	// Device is the device the step migrated, or the new modem's address for the step that programs it.
+	Device Address
+	// Done is the number of steps finished so far out of Total, including this one if it succeeded.
+	Done  int
+	Total int
+	// Records is the number of records written.
+	Records int
+	Err     error
+}
+
+// MigrationProgress is called as each step of a migration finishes.
+type MigrationProgress func(step *MigrationStep)
+
+// Migration moves a network from a modem that died to its replacement. The new modem is given the old one's records,
+// then every record on the devices that points at the old modem is pointed at the new one.
+//
+// A Migration is its own progress: steps that have finished are skipped when it's run again, so a migration that
+// couldn't reach every device can be saved as JSON and resumed later, for instance once sleeping battery devices have
+// been woken up. i2cs devices refuse to talk to a modem they have no link with, they'll fail until their SET button
+// has been used to link them to the new modem.
+type Migration struct {
+	// OldModem is the address of the modem being replaced.
+	OldModem Address
+	// Modem is the old modem's database, from a snapshot taken before it died or read from it if it's still running.
+	Modem *ModemSnapshot
+	// Devices are the devices to migrate.
+	Devices []Address
+
+	ModemMigrated bool
+	Migrated      map[Address]bool
+}
+
+// NewMigration creates a migration away from the modem in old. With no devices given, every device the old modem has
+// a record for is migrated.
+func NewMigration(old *ModemSnapshot, devices ...Address) *Migration {
+	if len(devices) == 0 {
+		seen := make(map[Address]bool)
+
+		for _, rec := range old.Links {
+			if !seen[rec.Address] {
+				seen[rec.Address] = true
+				devices = append(devices, rec.Address)
+			}
+		}
+	}
+
+	return &Migration{OldModem: old.Address, Modem: old, Devices: devices, Migrated: make(map[Address]bool)}
+}
+
+// Run runs the steps of the migration that haven't finished yet against the new modem links talks to. Devices that
+// fail are reported to progress, if not nil, and skipped, in which case ErrMigrationIncomplete is returned once the
+// rest are done.
+func (mg *Migration) Run(ctx context.Context, links *LinkManager, progress MigrationProgress) error {
+	if progress == nil {
+		progress = func(*MigrationStep) {}
+	}
+
+	if mg.Migrated == nil {
+		mg.Migrated = make(map[Address]bool)
+	}
+
+	links.mu.Lock()
+	modem, err := links.modemAddress(ctx)
+	links.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	step := &MigrationStep{Device: modem, Done: mg.done(), Total: len(mg.Devices) + 1}
+
+	if !mg.ModemMigrated {
+		if step.Records, step.Err = mg.programModem(ctx, links); step.Err != nil {
+			progress(step)
+
+			return step.Err
+		}
+
+		mg.ModemMigrated = true
+		step.Done++
+		progress(step)
+	}
+
+	var failed []Address
+
+	for _, addr := range mg.Devices {
+		if mg.Migrated[addr] {
+			continue
+		}
+
+		step = &MigrationStep{Device: addr, Done: step.Done, Total: step.Total}
+
+		if step.Records, step.Err = mg.migrateDevice(ctx, links, addr, modem); step.Err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			failed = append(failed, addr)
+			progress(step)
+
+			continue
+		}
+
+		mg.Migrated[addr] = true
+		step.Done++
+		progress(step)
+	}
+
+	if len(failed) > 0 {
+		return errors.Wrapf(ErrMigrationIncomplete, "devices: %s", failed)
+	}
+
+	return nil
+}
+
+// done returns the number of steps that have finished.
+func (mg *Migration) done() int {
+	done := 0
+	if mg.ModemMigrated {
+		done++
+	}
+
+	for _, addr := range mg.Devices {
+		if mg.Migrated[addr] {
+			done++
+		}
+	}
+
+	return done
+}
+
+// programModem gives the new modem the old modem's records, returning the number of records written.
+func (mg *Migration) programModem(ctx context.Context, links *LinkManager) (int, error) {
+	links.mu.Lock()
+	defer links.mu.Unlock()
+
+	modem, err := links.modemAddress(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	db, err := links.modemLinkDB(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+
+	for _, rec := range uniqueLinks(mg.Modem.Links) {
+		role := LinkCodeResponder
+		if rec.Flags.Controller() {
+			role = LinkCodeController
+		}
+
+		if cur, ok := db.Find(rec.Address, rec.Group, role); ok && cur.Data == rec.Data {
+			continue
+		}
+
+		if _, err := links.ensure(ctx, modem, rec.Address, rec.Group, rec.Flags.Controller(), rec.Data); err != nil {
+			return written, err
+		}
+
+		written++
+	}
+
+	return written, nil
+}
+
+// migrateDevice points the records of the device at addr that are for the old modem at the new one, returning the
+// number of records written.
+func (mg *Migration) migrateDevice(ctx context.Context, links *LinkManager, addr, modem Address) (int, error) {
+	db, err := links.Device(addr).GetLinkDatabase(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	db.repoint(mg.OldModem, modem)
+
+	written := len(db.Plan())
+
+	return written, db.Apply(ctx)
+}
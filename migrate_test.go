@@ -0,0 +1,102 @@
+package insteon_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type MigrationTestSuite struct {
+	suite.Suite
+	mock  *InsteonHubMock
+	links *insteon.LinkManager
+}
+
+func (s *MigrationTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.links = insteon.NewLinkManager(hub)
+}
+
+func (s *MigrationTestSuite) TestRunResume() {
+	oldModem, newModem := insteon.Address{0x11, 0x22, 0x33}, insteon.Address{0x01, 0x02, 0x03}
+	device := insteon.Address{0xAA, 0xBB, 0xCC}
+
+	migration := insteon.NewMigration(&insteon.ModemSnapshot{Address: oldModem, Links: []*insteon.AllLinkRecord{
+		{Flags: 0xE2, Group: 1, Address: device},
+		{Flags: 0xA2, Group: 1, Address: device},
+	}})
+	s.Require().Equal([]insteon.Address{device}, migration.Devices)
+
+	// The new modem is empty and gets both records, then the device isn't ready.
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x6F, 0x40, 0xC2, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x6F, 0x40, 0xC2, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+		[]byte{0x02, 0x6F, 0x41, 0x82, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x6F, 0x41, 0x82, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x0D, 0x00},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x0D, 0x00, 0x15},
+	)
+
+	var steps []*insteon.MigrationStep
+
+	progress := func(step *insteon.MigrationStep) { steps = append(steps, step) }
+
+	err := migration.Run(s.mock.ctx, s.links, progress)
+	s.Require().ErrorIs(err, insteon.ErrMigrationIncomplete)
+	s.Require().Len(steps, 2)
+	s.Require().Equal(&insteon.MigrationStep{Device: newModem, Done: 1, Total: 2, Records: 2}, steps[0])
+	s.Require().Equal(device, steps[1].Device)
+	s.Require().Equal(1, steps[1].Done)
+	s.Require().ErrorIs(steps[1].Err, insteon.ErrNotReady)
+
+	// The migration picks up where it left off after a trip through JSON.
+	buf, err := json.Marshal(migration)
+	s.Require().NoError(err)
+
+	resumed := &insteon.Migration{}
+	s.Require().NoError(json.NewDecoder(bytes.NewReader(buf)).Decode(resumed))
+	s.Require().True(resumed.ModemMigrated)
+
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device,
+		[]byte{0x0F, 0xFF, 0xA2, 0x01, 0x11, 0x22, 0x33, 0xFF, 0x1C, 0x01},
+		[]byte{0x0F, 0xF7, 0xE2, 0x01, 0x11, 0x22, 0x33, 0x00, 0x00, 0x01},
+		[]byte{0x0F, 0xEF, 0xE2, 0x02, 0x99, 0x88, 0x77, 0x00, 0x00, 0x02},
+	)
+
+	controller := []byte{0x0F, 0xF7, 0xE2, 0x01, 0x01, 0x02, 0x03, 0x00, 0x00, 0x01}
+	responder := []byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01}
+
+	s.mock.ExpectRecordWrite(device, controller, controller)
+	s.mock.ExpectRecordWrite(device, responder, responder)
+
+	steps = nil
+
+	s.Require().NoError(resumed.Run(s.mock.ctx, s.links, progress))
+	s.Require().Equal([]*insteon.MigrationStep{{Device: device, Done: 2, Total: 2, Records: 2}}, steps)
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+
+	// Nothing is left to do.
+	steps = nil
+
+	s.Require().NoError(resumed.Run(s.mock.ctx, s.links, progress))
+	s.Require().Empty(steps)
+}
+
+func TestMigrationTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(MigrationTestSuite))
+}
@@ -0,0 +1,264 @@
+package insteon
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrModemDBAddresses indicates an operation needs to know where the modem keeps its records, which it didn't report.
+var ErrModemDBAddresses = errors.New("modem didn't report where its records are kept")
+
+const (
+	// ModemLinkCapacity is the number of records a 2413 class modem can hold.
+	ModemLinkCapacity = 992
+
+	// modemDBFirstRecord is the address of the first record of a 2413 class modem's database, it grows down from there.
+	modemDBFirstRecord uint16 = 0x1FF8
+	// modemDBReadTimeout is how long to wait for a record before deciding the modem can't read its memory directly.
+	modemDBReadTimeout = 2 * time.Second
+)
+
+// modemRecord is a record of the modem's database and where it's kept, if that's known.
+type modemRecord struct {
+	memAddr uint16
+	rec     *AllLinkRecord
+}
+
+// ModemLinkDB is a copy of the modem's All-Link database. It's read with Load and kept up to date as it's changed.
+//
+// The database is read straight out of the modem's memory when possible, which is faster and tells us where each
+// record is kept. Modems that can't do that are read a record at a time, and some operations aren't available.
+type ModemLinkDB struct {
+	hub     Hub
+	records []*modemRecord
+}
+
+// NewModemLinkDB creates a new copy of the modem's database, which is empty until it's loaded.
+func NewModemLinkDB(hub Hub) *ModemLinkDB {
+	return &ModemLinkDB{hub: hub}
+}
+
+// Load reads the modem's database.
+func (db *ModemLinkDB) Load(ctx context.Context) error {
+	records, err := db.scan(ctx)
+	if err == nil {
+		db.records = records
+
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return err
+	}
+
+	// The modem can't read its memory directly, fall back to asking for one record after another.
+	recs, err := db.hub.GetAllLinkDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	db.records = nil
+	for _, rec := range recs {
+		db.records = append(db.records, &modemRecord{rec: rec})
+	}
+
+	return nil
+}
+
+// scan reads the database out of the modem's memory until it reaches the high water record.
+func (db *ModemLinkDB) scan(ctx context.Context) ([]*modemRecord, error) {
+	var records []*modemRecord
+
+	memAddr := modemDBFirstRecord
+
+	for idx := 0; idx < ModemLinkCapacity; idx++ {
+		readCtx, cancel := context.WithTimeout(ctx, modemDBReadTimeout)
+		rsp, err := db.hub.ReadDB(readCtx, memAddr)
+
+		cancel()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if rsp.Record.Flags.HighWater() {
+			break
+		}
+
+		records = append(records, &modemRecord{memAddr: memAddr, rec: rsp.Record})
+		memAddr -= dbRecordSize
+	}
+
+	return records, nil
+}
+
+// Records returns the records in use.
+func (db *ModemLinkDB) Records() []*AllLinkRecord {
+	var recs []*AllLinkRecord
+
+	for _, r := range db.records {
+		if r.rec.Flags.InUse() {
+			rec := *r.rec
+			recs = append(recs, &rec)
+		}
+	}
+
+	return recs
+}
+
+// Used returns the number of records in use.
+func (db *ModemLinkDB) Used() int {
+	return len(db.Records())
+}
+
+// Free returns the number of records that can still be added.
+func (db *ModemLinkDB) Free() int {
+	return ModemLinkCapacity - db.Used()
+}
+
+// Find returns the record for addr and group where the modem has the given role, or false if there isn't one.
+func (db *ModemLinkDB) Find(addr Address, group byte, role LinkCode) (*AllLinkRecord, bool) {
+	if r := db.find(addr, group, role); r != nil {
+		rec := *r.rec
+
+		return &rec, true
+	}
+
+	return nil, false
+}
+
+func (db *ModemLinkDB) find(addr Address, group byte, role LinkCode) *modemRecord {
+	for _, r := range db.records {
+		if r.rec.Flags.InUse() && r.rec.Address == addr && r.rec.Group == group &&
+			r.rec.Flags.Controller() == (role == LinkCodeController) {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// AddController adds a record making the modem a controller of addr for group.
+func (db *ModemLinkDB) AddController(ctx context.Context, addr Address, group byte, data [3]byte) error {
+	return db.add(ctx, ManageAllLinkAddController, addr, group, LinkCodeController, data)
+}
+
+// AddResponder adds a record making the modem a responder to addr for group.
+func (db *ModemLinkDB) AddResponder(ctx context.Context, addr Address, group byte, data [3]byte) error {
+	return db.add(ctx, ManageAllLinkAddResponder, addr, group, LinkCodeResponder, data)
+}
+
+func (db *ModemLinkDB) add(ctx context.Context, cmd ManageAllLinkCommand, addr Address, group byte, role LinkCode,
+	data [3]byte) error {
+	if db.find(addr, group, role) != nil {
+		return errors.Wrapf(ErrDBEntryAlreadyExists, "address: %s, group: %d, role: %d", addr, group, role)
+	}
+
+	rec := newLinkRecord(addr, group, role == LinkCodeController, data)
+
+	if err := db.hub.ModifyAllLinkEntry(ctx, cmd, rec.Flags, group, addr, data); err != nil {
+		return err
+	}
+
+	// The modem decides where the record goes.
+	db.records = append(db.records, &modemRecord{rec: rec})
+
+	return nil
+}
+
+// Update changes the data of the record for addr and group where the modem has the given role.
+func (db *ModemLinkDB) Update(ctx context.Context, addr Address, group byte, role LinkCode, data [3]byte) error {
+	r := db.find(addr, group, role)
+	if r == nil {
+		return errors.Wrapf(ErrDBEntryNotFound, "address: %s, group: %d, role: %d", addr, group, role)
+	}
+
+	rec := *r.rec
+	rec.Data = data
+
+	if err := db.write(ctx, r, &rec, ManageAllLinkUpdate); err != nil {
+		return err
+	}
+
+	r.rec = &rec
+
+	return nil
+}
+
+// Delete removes the record for addr and group where the modem has the given role.
+func (db *ModemLinkDB) Delete(ctx context.Context, addr Address, group byte, role LinkCode) error {
+	r := db.find(addr, group, role)
+	if r == nil {
+		return errors.Wrapf(ErrDBEntryNotFound, "address: %s, group: %d, role: %d", addr, group, role)
+	}
+
+	rec := *r.rec
+	rec.Flags &^= AllLinkRecordFlagsInUse
+
+	if err := db.write(ctx, r, &rec, ManageAllLinkDelete); err != nil {
+		return err
+	}
+
+	r.rec = &rec
+
+	return nil
+}
+
+// write replaces a record in place when it's known where it's kept. Otherwise cmd is used, which changes the first
+// record with the same address and group, so it has to be the only one.
+func (db *ModemLinkDB) write(ctx context.Context, r *modemRecord, rec *AllLinkRecord, cmd ManageAllLinkCommand) error {
+	if r.memAddr != 0 {
+		return db.hub.WriteDB(ctx, r.memAddr, rec)
+	}
+
+	for _, other := range db.records {
+		if other != r && other.rec.Flags.InUse() && other.rec.Address == rec.Address && other.rec.Group == rec.Group {
+			return errors.Wrapf(ErrModemDBAddresses, "address: %s, group: %d", rec.Address, rec.Group)
+		}
+	}
+
+	return db.hub.ModifyAllLinkEntry(ctx, cmd, rec.Flags, rec.Group, rec.Address, rec.Data)
+}
+
+// Compact reloads the database and moves the records in use to the top, so the deleted records no longer take up
+// space. Records are only ever moved up, over ones that are deleted or already moved, so stopping part way leaves at
+// worst a duplicate record behind.
+func (db *ModemLinkDB) Compact(ctx context.Context) error {
+	records, err := db.scan(ctx)
+	if err != nil {
+		return errors.Wrapf(ErrModemDBAddresses, "%s", err)
+	}
+
+	compacted := make([]*modemRecord, 0, len(records))
+	memAddr := modemDBFirstRecord
+
+	for _, r := range records {
+		if !r.rec.Flags.InUse() {
+			continue
+		}
+
+		if r.memAddr != memAddr {
+			if err := db.hub.WriteDB(ctx, memAddr, r.rec); err != nil {
+				return err
+			}
+		}
+
+		compacted = append(compacted, &modemRecord{memAddr: memAddr, rec: r.rec})
+		memAddr -= dbRecordSize
+	}
+
+	// Everything past the records in use becomes unused, starting with the new high water record.
+	for idx := len(compacted); idx < len(records); idx++ {
+		if err := db.hub.WriteDB(ctx, memAddr, &AllLinkRecord{}); err != nil {
+			return err
+		}
+
+		memAddr -= dbRecordSize
+	}
+
+	db.records = compacted
+
+	return nil
+}
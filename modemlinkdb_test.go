@@ -0,0 +1,130 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type ModemLinkDBTestSuite struct {
+	suite.Suite
+	mock *InsteonHubMock
+	db   *insteon.ModemLinkDB
+}
+
+func (s *ModemLinkDBTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.db = insteon.NewModemLinkDB(hub)
+}
+
+// expectScan scripts the modem's memory holding a deleted record, then a controller and a responder record for
+// AA.BB.CC.
+func (s *ModemLinkDBTestSuite) expectScan() {
+	s.mock.Expect(
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0x22, 0x03, 0x11, 0x22, 0x33, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xF0},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF0, 0x06,
+			0x02, 0x59, 0x1F, 0xF0, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xE8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xE8, 0x06,
+			0x02, 0x59, 0x1F, 0xE8, 0xA2, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xE0},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xE0, 0x06,
+			0x02, 0x59, 0x1F, 0xE0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	)
+}
+
+func (s *ModemLinkDBTestSuite) TestLoad() {
+	s.expectScan()
+
+	s.Require().NoError(s.db.Load(s.mock.ctx))
+	s.Require().Equal(2, s.db.Used())
+	s.Require().Equal(insteon.ModemLinkCapacity-2, s.db.Free())
+
+	rec, ok := s.db.Find(insteon.Address{0xAA, 0xBB, 0xCC}, 1, insteon.LinkCodeController)
+	s.Require().True(ok)
+	s.Require().Equal([3]byte{0x01, 0x2E, 0x45}, rec.Data)
+
+	_, ok = s.db.Find(insteon.Address{0x11, 0x22, 0x33}, 3, insteon.LinkCodeResponder)
+	s.Require().False(ok)
+}
+
+func (s *ModemLinkDBTestSuite) TestLoadFallback() {
+	// The modem refuses to read its memory, so the records are read one after another.
+	s.mock.Expect(
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{0x02, 0x75, 0x1F, 0xF8, 0x15},
+		[]byte{0x02, 0x69},
+		[]byte{0x02, 0x69, 0x06, 0x02, 0x57, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45},
+		[]byte{0x02, 0x6a},
+		[]byte{0x02, 0x6a, 0x15},
+	)
+
+	s.Require().NoError(s.db.Load(s.mock.ctx))
+	s.Require().Equal(1, s.db.Used())
+}
+
+func (s *ModemLinkDBTestSuite) TestChanges() {
+	s.expectScan()
+	s.Require().NoError(s.db.Load(s.mock.ctx))
+
+	addr := insteon.Address{0xAA, 0xBB, 0xCC}
+
+	s.mock.Expect(
+		// Adding goes through the modem.
+		[]byte{0x02, 0x6F, 0x41, 0x82, 0x02, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x6F, 0x41, 0x82, 0x02, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+		// Records whose place is known are changed in place.
+		[]byte{0x02, 0x76, 0x1F, 0xF0, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x46},
+		[]byte{0x02, 0x76, 0x1F, 0xF0, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x46, 0x06},
+		[]byte{0x02, 0x76, 0x1F, 0xE8, 0x22, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x76, 0x1F, 0xE8, 0x22, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+		// The new record's place isn't known, but it's the only one for its group.
+		[]byte{0x02, 0x6F, 0x80, 0x02, 0x02, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x6F, 0x80, 0x02, 0x02, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+	)
+
+	s.Require().NoError(s.db.AddResponder(s.mock.ctx, addr, 2, [3]byte{}))
+	s.Require().ErrorIs(s.db.AddResponder(s.mock.ctx, addr, 2, [3]byte{}), insteon.ErrDBEntryAlreadyExists)
+	s.Require().NoError(s.db.Update(s.mock.ctx, addr, 1, insteon.LinkCodeController, [3]byte{0x01, 0x2E, 0x46}))
+	s.Require().NoError(s.db.Delete(s.mock.ctx, addr, 1, insteon.LinkCodeResponder))
+	s.Require().NoError(s.db.Delete(s.mock.ctx, addr, 2, insteon.LinkCodeResponder))
+	s.Require().ErrorIs(s.db.Delete(s.mock.ctx, addr, 2, insteon.LinkCodeResponder), insteon.ErrDBEntryNotFound)
+	s.Require().Equal(1, s.db.Used())
+}
+
+func (s *ModemLinkDBTestSuite) TestCompact() {
+	s.expectScan()
+
+	// Both records move up over the deleted one and the last place becomes the end of the database.
+	s.mock.Expect(
+		[]byte{0x02, 0x76, 0x1F, 0xF8, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45},
+		[]byte{0x02, 0x76, 0x1F, 0xF8, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x2E, 0x45, 0x06},
+		[]byte{0x02, 0x76, 0x1F, 0xF0, 0xA2, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x76, 0x1F, 0xF0, 0xA2, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+		[]byte{0x02, 0x76, 0x1F, 0xE8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x76, 0x1F, 0xE8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06},
+	)
+
+	s.Require().NoError(s.db.Compact(s.mock.ctx))
+	s.Require().Equal(2, s.db.Used())
+}
+
+func TestModemLinkDBSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &ModemLinkDBTestSuite{})
+}
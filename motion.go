@@ -0,0 +1,232 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// These are the groups a motion sensor broadcasts on.
+const (
+	motionGroupMotion     byte = 1
+	motionGroupDusk       byte = 2
+	motionGroupLowBattery byte = 3
+)
+
+// These are the extended configuration settings of a motion sensor and the offsets of their current values in the
+// extended configuration data.
+const (
+	motionConfigLEDBrightness    byte = 0x02
+	motionConfigTimeout          byte = 0x03
+	motionConfigLightSensitivity byte = 0x04
+	motionConfigFlags            byte = 0x05
+
+	motionDataLEDBrightness    = 2
+	motionDataTimeout          = 3
+	motionDataLightSensitivity = 4
+	motionDataFlags            = 5
+	motionDataBattery          = 11
+)
+
+// These bits are reported in the motion sensor's configuration flags.
+const (
+	motionFlagNightOnly byte = 0x02
+	motionFlagOnOnly    byte = 0x04
+)
+
+// motionTimeoutStep is the resolution of the motion sensor's timeout.
+const motionTimeoutStep = 30 * time.Second
+
+// MotionEventType describes what changed in a MotionEvent.
+type MotionEventType int
+
+const (
+	// MotionEventMotion is generated when motion is detected (On) or the timeout expires (Off).
+	MotionEventMotion MotionEventType = iota
+	// MotionEventDusk is generated when it gets dark (On) or light (Off).
+	MotionEventDusk
+	// MotionEventLowBattery is generated when the battery is low (On) or has been replaced (Off).
+	MotionEventLowBattery
+)
+
+func (t MotionEventType) String() string {
+	switch t {
+	case MotionEventMotion:
+		return "Motion"
+	case MotionEventDusk:
+		return "Dusk"
+	case MotionEventLowBattery:
+		return "Low Battery"
+	default:
+		return "Unknown"
+	}
+}
+
+// MotionEvent is generated when a motion sensor broadcasts a change.
+type MotionEvent struct {
+	deviceEvent
+	Type MotionEventType
+	On   bool
+}
+
+// MotionSensorConfig is the current configuration of a motion sensor.
+type MotionSensorConfig struct {
+	// LEDBrightness is the brightness of the LED that flashes when motion is detected.
+	LEDBrightness byte
+	// Timeout is how long the sensor waits without motion before sending off.
+	Timeout time.Duration
+	// LightSensitivity is the light level that's considered dusk.
+	LightSensitivity byte
+	// NightOnly indicates the sensor only reports motion when it's dark.
+	NightOnly bool
+	// OnOnly indicates the sensor never sends off commands.
+	OnOnly bool
+	// Battery is the raw battery level reported by the sensor.
+	Battery byte
+}
+
+func (c *MotionSensorConfig) String() string {
+	return fmt.Sprintf("LEDBrightness=%d, Timeout=%s, LightSensitivity=%d, NightOnly=%t, OnOnly=%t, Battery=%d",
+		c.LEDBrightness, c.Timeout, c.LightSensitivity, c.NightOnly, c.OnOnly, c.Battery)
+}
+
+// MotionSensor represents a battery powered motion sensor such as the 2842. The sensor only listens for a few seconds
+// after it transmits, so configuration reads and writes are queued until the sensor reports motion (or is woken by
+// holding its SET button) and then sent while it's awake.
+type MotionSensor struct {
+	*Device
+	events deviceEvents
+	queue  awakeQueue
+}
+
+// NewMotionSensor creates a new motion sensor by raw address.
+func NewMotionSensor(hub Hub, addr Address) (*MotionSensor, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MotionSensor{Device: dev}, nil
+}
+
+// Pending returns the number of configuration commands waiting for the sensor to wake up.
+func (m *MotionSensor) Pending() int {
+	return m.queue.len()
+}
+
+// GetConfig reads the sensor's configuration the next time it's awake.
+func (m *MotionSensor) GetConfig(ctx context.Context) (*MotionSensorConfig, error) {
+	var cfg *MotionSensorConfig
+
+	err := m.whenAwake(ctx, func(ctx context.Context) error {
+		data, err := m.GetExtendedConfig(ctx, 0)
+		if err != nil {
+			return err
+		}
+
+		cfg = &MotionSensorConfig{
+			LEDBrightness:    data[motionDataLEDBrightness],
+			Timeout:          time.Duration(int(data[motionDataTimeout])+1) * motionTimeoutStep,
+			LightSensitivity: data[motionDataLightSensitivity],
+			NightOnly:        data[motionDataFlags]&motionFlagNightOnly > 0,
+			OnOnly:           data[motionDataFlags]&motionFlagOnOnly > 0,
+			Battery:          data[motionDataBattery],
+		}
+
+		return nil
+	})
+
+	return cfg, err
+}
+
+// SetLEDBrightness sets the brightness of the sensor's LED the next time it's awake.
+func (m *MotionSensor) SetLEDBrightness(ctx context.Context, level byte) error {
+	return m.whenAwake(ctx, func(ctx context.Context) error {
+		return m.SetExtendedConfig(ctx, 0, motionConfigLEDBrightness, level)
+	})
+}
+
+// SetTimeout sets how long the sensor waits without motion before sending off, the next time it's awake. The timeout
+// is rounded down to a multiple of 30 seconds.
+func (m *MotionSensor) SetTimeout(ctx context.Context, timeout time.Duration) error {
+	const maxSteps = 0x100
+
+	steps := timeout / motionTimeoutStep
+	if steps < 1 || steps > maxSteps {
+		return fmt.Errorf("motion sensor timeout out of range: %s", timeout)
+	}
+
+	return m.whenAwake(ctx, func(ctx context.Context) error {
+		return m.SetExtendedConfig(ctx, 0, motionConfigTimeout, byte(steps-1))
+	})
+}
+
+// SetLightSensitivity sets the light level that's considered dusk the next time the sensor is awake.
+func (m *MotionSensor) SetLightSensitivity(ctx context.Context, level byte) error {
+	return m.whenAwake(ctx, func(ctx context.Context) error {
+		return m.SetExtendedConfig(ctx, 0, motionConfigLightSensitivity, level)
+	})
+}
+
+// SetNightOnly sets whether the sensor only reports motion when it's dark, the next time the sensor is awake.
+func (m *MotionSensor) SetNightOnly(ctx context.Context, nightOnly bool) error {
+	return m.whenAwake(ctx, func(ctx context.Context) error {
+		data, err := m.GetExtendedConfig(ctx, 0)
+		if err != nil {
+			return err
+		}
+
+		flags := data[motionDataFlags] &^ motionFlagNightOnly
+		if nightOnly {
+			flags |= motionFlagNightOnly
+		}
+
+		return m.SetExtendedConfig(ctx, 0, motionConfigFlags, flags)
+	})
+}
+
+// AddListener registers a listener that's notified with a MotionEvent whenever the sensor broadcasts a change.
+func (m *MotionSensor) AddListener(listener DeviceEventListener) {
+	m.events.add(listener)
+	m.events.watch(m.hub, m.address, m.handleMessage)
+}
+
+func (m *MotionSensor) whenAwake(ctx context.Context, op func(context.Context) error) error {
+	m.events.watch(m.hub, m.address, m.handleMessage)
+
+	return m.queue.do(ctx, op)
+}
+
+func (m *MotionSensor) handleMessage(rsp CommandResponse) {
+	group, ok := groupBroadcast(rsp)
+	if !ok {
+		return
+	}
+
+	// Any broadcast means the sensor is listening for a little while.
+	m.queue.awake()
+
+	evt := &MotionEvent{deviceEvent: deviceEvent{from: m.address}}
+
+	switch group {
+	case motionGroupMotion:
+		evt.Type = MotionEventMotion
+	case motionGroupDusk:
+		evt.Type = MotionEventDusk
+	case motionGroupLowBattery:
+		evt.Type = MotionEventLowBattery
+	default:
+		return
+	}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn:
+		evt.On = true
+	case cmdControlOff:
+		evt.On = false
+	default:
+		return
+	}
+
+	m.events.emit(evt)
+}
@@ -0,0 +1,127 @@
+package insteon_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type MotionSensorTestSuite struct {
+	suite.Suite
+	mock   *InsteonHubMock
+	sensor *insteon.MotionSensor
+}
+
+func (s *MotionSensorTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.sensor, err = insteon.NewMotionSensor(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *MotionSensorTestSuite) TestQueuedUntilAwake() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x00, 0x00, 0x02, 0x40, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x8E},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x00, 0x00, 0x02, 0x40, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x8E, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2E, 0x00,
+		},
+	)
+
+	events := make(chan *insteon.MotionEvent, 1)
+
+	s.sensor.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.MotionEvent)
+	})
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.sensor.SetLEDBrightness(s.mock.ctx, 0x40)
+	}()
+
+	s.Require().Eventually(func() bool { return s.sensor.Pending() == 1 }, time.Second, 10*time.Millisecond)
+
+	// The sensor reports motion and is briefly awake.
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x01, 0xCF, 0x11, 0x00})
+	}()
+
+	var evt *insteon.MotionEvent
+
+	select {
+	case evt = <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Equal(insteon.MotionEventMotion, evt.Type)
+	s.Require().True(evt.On)
+
+	select {
+	case err := <-done:
+		s.Require().NoError(err)
+	case <-time.After(time.Second):
+		s.FailNow("queued command wasn't sent")
+	}
+	s.Require().Equal(0, s.sensor.Pending())
+}
+
+func (s *MotionSensorTestSuite) TestCancelledBeforeAwake() {
+	events := make(chan *insteon.MotionEvent, 1)
+
+	s.sensor.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.MotionEvent)
+	})
+
+	ctx, cancel := context.WithCancel(s.mock.ctx)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.sensor.SetLEDBrightness(ctx, 0x40)
+	}()
+
+	s.Require().Eventually(func() bool { return s.sensor.Pending() == 1 }, time.Second, 10*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		s.Require().ErrorIs(err, context.Canceled)
+	case <-time.After(time.Second):
+		s.FailNow("cancelled command didn't return")
+	}
+
+	s.Require().Equal(0, s.sensor.Pending())
+
+	// Waking the sensor up must not send the cancelled command.
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x01, 0xCF, 0x11, 0x00})
+	}()
+
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	s.Require().Zero(s.mock.inBuffer.Len())
+}
+
+func (s *MotionSensorTestSuite) TestTimeoutRange() {
+	s.Require().Error(s.sensor.SetTimeout(s.mock.ctx, time.Second))
+}
+
+func TestMotionSensorSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &MotionSensorTestSuite{})
+}
@@ -0,0 +1,188 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+)
+
+// These are the groups and status channels used by dual outlets and relays with sense.
+const (
+	outletStatusChannel byte = 1
+
+	senseGroup         byte = 2
+	senseStatusChannel byte = 1
+)
+
+// Outlet identifies one of the outlets of a dual outlet.
+type Outlet byte
+
+const (
+	OutletTop    Outlet = 1
+	OutletBottom Outlet = 2
+)
+
+func (o Outlet) String() string {
+	switch o {
+	case OutletTop:
+		return "Top"
+	case OutletBottom:
+		return "Bottom"
+	default:
+		return "Unknown"
+	}
+}
+
+// mask returns the bit used for this outlet in the status response.
+func (o Outlet) mask() byte {
+	return 1 << (o - 1)
+}
+
+// OutletStatus is the state of both outlets of a dual outlet.
+type OutletStatus struct {
+	Top    bool
+	Bottom bool
+}
+
+func (s *OutletStatus) String() string {
+	return fmt.Sprintf("Top=%t, Bottom=%t", s.Top, s.Bottom)
+}
+
+// OutletEvent is generated when one of the outlets of a dual outlet is turned on or off at the device.
+type OutletEvent struct {
+	deviceEvent
+	Outlet Outlet
+	On     bool
+}
+
+// OutletLinc represents a dual outlet such as the On/Off Outlet [2663-222] (subcategory 0x39 of
+// CategorySwitchedLighting), where the top and bottom outlets are controlled independently. The embedded device only
+// addresses the top outlet. Relays with sense have a single load and are handled by SenseRelay instead.
+type OutletLinc struct {
+	*Device
+	events deviceEvents
+}
+
+// NewOutletLinc creates a new dual outlet by raw address.
+func NewOutletLinc(hub Hub, addr Address) (*OutletLinc, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutletLinc{Device: dev}, nil
+}
+
+// SetOutlet turns one of the outlets on or off.
+func (o *OutletLinc) SetOutlet(ctx context.Context, outlet Outlet, on bool) error {
+	if outlet != OutletTop && outlet != OutletBottom {
+		return fmt.Errorf("unknown outlet: %d", outlet)
+	}
+
+	ctlCmd, level := cmdControlOff, byte(0)
+	if on {
+		ctlCmd, level = cmdControlOn, 0xFF
+	}
+
+	_, err := o.sendExtended(ctx, ctlCmd, level, [14]byte{byte(outlet)})
+
+	return err
+}
+
+// GetOutlets gets the current state of both outlets.
+func (o *OutletLinc) GetOutlets(ctx context.Context) (*OutletStatus, error) {
+	status, err := o.GetStatusChannel(ctx, outletStatusChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutletStatus{
+		Top:    status.Level&OutletTop.mask() > 0,
+		Bottom: status.Level&OutletBottom.mask() > 0,
+	}, nil
+}
+
+// AddListener registers a listener that's notified with an OutletEvent whenever an outlet is switched at the device.
+func (o *OutletLinc) AddListener(listener DeviceEventListener) {
+	o.events.add(listener)
+	o.events.watch(o.hub, o.address, o.handleMessage)
+}
+
+func (o *OutletLinc) handleMessage(rsp CommandResponse) {
+	group, ok := groupBroadcast(rsp)
+	if !ok || (Outlet(group) != OutletTop && Outlet(group) != OutletBottom) {
+		return
+	}
+
+	evt := &OutletEvent{deviceEvent: deviceEvent{from: o.address}, Outlet: Outlet(group)}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn, cmdControlFastOn:
+		evt.On = true
+	case cmdControlOff, cmdControlFastOff:
+		evt.On = false
+	default:
+		return
+	}
+
+	o.events.emit(evt)
+}
+
+// SenseEvent is generated when the sense input of a relay with sense changes.
+type SenseEvent struct {
+	deviceEvent
+	// Sense is true if the sense input is on.
+	Sense bool
+}
+
+// SenseRelay represents a relay with a sense input, the In-LineLinc Relay with Sense [2475S2] and the SwitchLinc Relay
+// with Sense [2476S2] (subcategories 0x14 and 0x15 of CategorySwitchedLighting). The embedded device controls the
+// relay.
+type SenseRelay struct {
+	*Device
+	events deviceEvents
+}
+
+// NewSenseRelay creates a new relay with sense by raw address.
+func NewSenseRelay(hub Hub, addr Address) (*SenseRelay, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SenseRelay{Device: dev}, nil
+}
+
+// GetSense returns true if the sense input is currently on.
+func (r *SenseRelay) GetSense(ctx context.Context) (bool, error) {
+	status, err := r.GetStatusChannel(ctx, senseStatusChannel)
+	if err != nil {
+		return false, err
+	}
+
+	return status.Level > 0, nil
+}
+
+// AddListener registers a listener that's notified with a SenseEvent whenever the sense input changes.
+func (r *SenseRelay) AddListener(listener DeviceEventListener) {
+	r.events.add(listener)
+	r.events.watch(r.hub, r.address, r.handleMessage)
+}
+
+func (r *SenseRelay) handleMessage(rsp CommandResponse) {
+	if group, ok := groupBroadcast(rsp); !ok || group != senseGroup {
+		return
+	}
+
+	evt := &SenseEvent{deviceEvent: deviceEvent{from: r.address}}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn, cmdControlFastOn:
+		evt.Sense = true
+	case cmdControlOff, cmdControlFastOff:
+		evt.Sense = false
+	default:
+		return
+	}
+
+	r.events.emit(evt)
+}
@@ -0,0 +1,73 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type OutletLincTestSuite struct {
+	suite.Suite
+	mock   *InsteonHubMock
+	outlet *insteon.OutletLinc
+}
+
+func (s *OutletLincTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.outlet, err = insteon.NewOutletLinc(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *OutletLincTestSuite) TestSetOutlet() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x11, 0xFF, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xEC},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x11, 0xFF, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xEC, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x11, 0xFF,
+		},
+	)
+
+	s.Require().NoError(s.outlet.SetOutlet(s.mock.ctx, insteon.OutletBottom, true))
+}
+
+func (s *OutletLincTestSuite) TestSetOutletNAK() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x13, 0x00, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xEA},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x13, 0x00, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xEA, 0x06,
+			0x02, 0x50, 0x11, 0x22, 0x33, 0x01, 0x02, 0x03, 0x2B, 0x13, 0x00,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0xAB, 0x13, 0xFF,
+		},
+	)
+
+	s.Require().ErrorIs(s.outlet.SetOutlet(s.mock.ctx, insteon.OutletTop, false), insteon.ErrNAK)
+}
+
+func (s *OutletLincTestSuite) TestGetOutlets() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x01},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x19, 0x01, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x00, 0x02,
+		},
+	)
+
+	status, err := s.outlet.GetOutlets(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().False(status.Top)
+	s.Require().True(status.Bottom)
+}
+
+func TestOutletLincSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &OutletLincTestSuite{})
+}
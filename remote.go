@@ -0,0 +1,196 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// These are the offsets of the remote's settings in the extended configuration data.
+const (
+	remoteDataFlags   = 5
+	remoteDataBattery = 11
+
+	remoteFlagEightScene byte = 0x08
+)
+
+// RemoteMode is how the buttons on a remote are grouped.
+type RemoteMode int
+
+const (
+	// RemoteModeFourScene pairs the buttons so each pair controls one group, the left button sends on and the right
+	// button sends off.
+	RemoteModeFourScene RemoteMode = iota
+	// RemoteModeEightScene gives each button its own group.
+	RemoteModeEightScene
+)
+
+func (m RemoteMode) String() string {
+	switch m {
+	case RemoteModeFourScene:
+		return "4 Scene"
+	case RemoteModeEightScene:
+		return "8 Scene"
+	default:
+		return "Unknown"
+	}
+}
+
+// Buttons returns the number of groups the remote broadcasts on in this mode.
+func (m RemoteMode) Buttons() int {
+	if m == RemoteModeEightScene {
+		return 8
+	}
+
+	return 4
+}
+
+// RemoteConfig is the current configuration of a remote.
+type RemoteConfig struct {
+	Mode RemoteMode
+	// Battery is the raw battery level reported by the remote.
+	Battery byte
+}
+
+func (c *RemoteConfig) String() string {
+	return fmt.Sprintf("Mode=%s, Battery=%d", c.Mode, c.Battery)
+}
+
+// RemoteButtonEventType describes how a button on a remote was used.
+type RemoteButtonEventType int
+
+const (
+	// RemoteButtonPress is generated when a button is tapped.
+	RemoteButtonPress RemoteButtonEventType = iota
+	// RemoteButtonDoublePress is generated when a button is tapped twice quickly.
+	RemoteButtonDoublePress
+	// RemoteButtonHoldStart is generated when a button is pressed and held.
+	RemoteButtonHoldStart
+	// RemoteButtonHoldStop is generated when a held button is released.
+	RemoteButtonHoldStop
+)
+
+func (t RemoteButtonEventType) String() string {
+	switch t {
+	case RemoteButtonPress:
+		return "Press"
+	case RemoteButtonDoublePress:
+		return "Double Press"
+	case RemoteButtonHoldStart:
+		return "Hold Start"
+	case RemoteButtonHoldStop:
+		return "Hold Stop"
+	default:
+		return "Unknown"
+	}
+}
+
+// RemoteButtonEvent is generated when a button on a remote is used.
+type RemoteButtonEvent struct {
+	deviceEvent
+	// Button is the group the button broadcasts on.
+	Button int
+	Type   RemoteButtonEventType
+	// On is true for on presses and holds that brighten, a hold stop carries the direction of the hold it ends.
+	On bool
+}
+
+// Remote represents a battery powered remote such as the mini remotes [2342] or the RemoteLinc 2 [2444A3]. The remote
+// only listens for a few seconds after a button is pressed, so configuration reads are queued until then.
+type Remote struct {
+	*Device
+	events deviceEvents
+	queue  awakeQueue
+
+	mu    sync.Mutex
+	holds map[int]bool
+}
+
+// NewRemote creates a new remote by raw address.
+func NewRemote(hub Hub, addr Address) (*Remote, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Remote{Device: dev, holds: map[int]bool{}}, nil
+}
+
+// Pending returns the number of configuration commands waiting for the remote to wake up.
+func (r *Remote) Pending() int {
+	return r.queue.len()
+}
+
+// GetConfig reads the remote's scene mode and battery level the next time it's awake.
+func (r *Remote) GetConfig(ctx context.Context) (*RemoteConfig, error) {
+	var cfg *RemoteConfig
+
+	err := r.whenAwake(ctx, func(ctx context.Context) error {
+		data, err := r.GetExtendedConfig(ctx, 0)
+		if err != nil {
+			return err
+		}
+
+		cfg = &RemoteConfig{Mode: RemoteModeFourScene, Battery: data[remoteDataBattery]}
+		if data[remoteDataFlags]&remoteFlagEightScene > 0 {
+			cfg.Mode = RemoteModeEightScene
+		}
+
+		return nil
+	})
+
+	return cfg, err
+}
+
+// AddListener registers a listener that's notified with a RemoteButtonEvent whenever a button is used.
+func (r *Remote) AddListener(listener DeviceEventListener) {
+	r.events.add(listener)
+	r.events.watch(r.hub, r.address, r.handleMessage)
+}
+
+func (r *Remote) whenAwake(ctx context.Context, op func(context.Context) error) error {
+	r.events.watch(r.hub, r.address, r.handleMessage)
+
+	return r.queue.do(ctx, op)
+}
+
+func (r *Remote) handleMessage(rsp CommandResponse) {
+	group, ok := groupBroadcast(rsp)
+	if !ok {
+		return
+	}
+
+	// Any broadcast means the remote is listening for a little while.
+	r.queue.awake()
+
+	evt := &RemoteButtonEvent{deviceEvent: deviceEvent{from: r.address}, Button: int(group)}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn:
+		evt.Type, evt.On = RemoteButtonPress, true
+	case cmdControlOff:
+		evt.Type, evt.On = RemoteButtonPress, false
+	case cmdControlFastOn:
+		evt.Type, evt.On = RemoteButtonDoublePress, true
+	case cmdControlFastOff:
+		evt.Type, evt.On = RemoteButtonDoublePress, false
+	case cmdControlStartDim:
+		// cmd2 is the direction of the hold, non-zero brightens.
+		evt.Type, evt.On = RemoteButtonHoldStart, rsp.Cmd2() > 0
+
+		r.mu.Lock()
+		r.holds[evt.Button] = evt.On
+		r.mu.Unlock()
+	case cmdControlStopDim:
+		evt.Type = RemoteButtonHoldStop
+
+		r.mu.Lock()
+		evt.On = r.holds[evt.Button]
+		delete(r.holds, evt.Button)
+		r.mu.Unlock()
+	default:
+		return
+	}
+
+	r.events.emit(evt)
+}
@@ -0,0 +1,102 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type RemoteTestSuite struct {
+	suite.Suite
+	mock   *InsteonHubMock
+	remote *insteon.Remote
+}
+
+func (s *RemoteTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.remote, err = insteon.NewRemote(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *RemoteTestSuite) TestButtonEvents() {
+	events := make(chan *insteon.RemoteButtonEvent, 1)
+
+	s.remote.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.RemoteButtonEvent)
+	})
+
+	tests := []struct {
+		msg      []byte
+		button   int
+		evtType  insteon.RemoteButtonEventType
+		expectOn bool
+	}{
+		{[]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x03, 0xCF, 0x11, 0x00}, 3, insteon.RemoteButtonPress, true},
+		{[]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x02, 0xCF, 0x14, 0x00}, 2, insteon.RemoteButtonDoublePress, false},
+		{[]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x01, 0xCF, 0x17, 0x01}, 1, insteon.RemoteButtonHoldStart, true},
+		{[]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x01, 0xCF, 0x18, 0x00}, 1, insteon.RemoteButtonHoldStop, true},
+	}
+
+	for _, test := range tests {
+		msg := test.msg
+
+		go func() {
+			_, _ = s.mock.outPipeOut.Write(msg)
+		}()
+
+		var evt *insteon.RemoteButtonEvent
+
+		select {
+		case evt = <-events:
+		case <-time.After(time.Second):
+			s.FailNow("event wasn't reported")
+		}
+
+		s.Require().Equal(test.button, evt.Button)
+		s.Require().Equal(test.evtType, evt.Type)
+		s.Require().Equal(test.expectOn, evt.On)
+	}
+}
+
+func (s *RemoteTestSuite) TestHoldStopFollowsStart() {
+	events := make(chan *insteon.RemoteButtonEvent, 2)
+
+	s.remote.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.RemoteButtonEvent)
+	})
+
+	// The stop carries no direction, it's taken from the start that arrived just before it.
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x02, 0xCF, 0x17, 0x01,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x02, 0xCF, 0x18, 0x00,
+		})
+	}()
+
+	for _, want := range []insteon.RemoteButtonEventType{insteon.RemoteButtonHoldStart, insteon.RemoteButtonHoldStop} {
+		var evt *insteon.RemoteButtonEvent
+
+		select {
+		case evt = <-events:
+		case <-time.After(time.Second):
+			s.FailNow("event wasn't reported")
+		}
+
+		s.Require().Equal(2, evt.Button)
+		s.Require().Equal(want, evt.Type)
+		s.Require().True(evt.On)
+	}
+}
+
+func TestRemoteSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &RemoteTestSuite{})
+}
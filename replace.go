@@ -0,0 +1,234 @@
+package insteon
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// These are the extended configuration settings of a dimmer and the offsets of their current values in the extended
+// configuration data.
+const (
+	dimmerConfigRampRate byte = 0x05
+	dimmerConfigOnLevel  byte = 0x06
+
+	dimmerDataRampRate = 6
+	dimmerDataOnLevel  = 7
+)
+
+// replaceSettingsTimeout is how long to wait for the old device's name and settings, not every device has them.
+const replaceSettingsTimeout = 5 * time.Second
+
+// ReplaceDevice moves everything from the device at oldAddr to its replacement at newAddr. Every record that points at
+// the old device, on the modem and on any device the modem or the old device has a record for, is pointed at the new
+// one. The new device gets the old one's records, and if the old device still answers, its name and, for dimmers, its
+// ramp rate and on level.
+//
+// When the old device can't be read, the new one gets the other half of every link its peers have with the old
+// device instead. All the databases are read before anything is written, and each one is changed in one go, so a
+// failure leaves at worst some databases changed and others not. Running it again finishes the job.
+func (m *LinkManager) ReplaceDevice(ctx context.Context, oldAddr, newAddr Address) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	modem, err := m.modemAddress(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.modemDB = nil
+
+	modemDB, err := m.modemLinkDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	old := m.device(oldAddr)
+
+	oldRecords, oldErr := old.GetDatabase(ctx)
+	if oldErr != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	var oldLinks []*AllLinkRecord
+
+	for _, r := range deviceAuditRecords(oldRecords) {
+		oldLinks = append(oldLinks, r.rec)
+	}
+
+	peers, err := m.replacementPeers(ctx, append(modemDB.Records(), oldLinks...), modem, oldAddr, newAddr)
+	if err != nil {
+		return err
+	}
+
+	newDB, err := m.device(newAddr).GetLinkDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	if oldErr != nil {
+		oldLinks = mirrorLinks(oldAddr, modem, modemDB.Records(), peers)
+	}
+
+	for _, rec := range uniqueLinks(oldLinks) {
+		if err := setLink(newDB, rec); err != nil {
+			return err
+		}
+	}
+
+	if err := newDB.Apply(ctx); err != nil {
+		return err
+	}
+
+	for _, db := range peers {
+		db.repoint(oldAddr, newAddr)
+
+		if err := db.Apply(ctx); err != nil {
+			return errors.Wrapf(err, "address: %s", db.device.address)
+		}
+	}
+
+	for _, rec := range modemDB.Records() {
+		if rec.Address != oldAddr {
+			continue
+		}
+
+		controller := rec.Flags.Controller()
+
+		if _, err := m.ensure(ctx, modem, newAddr, rec.Group, controller, rec.Data); err != nil {
+			return err
+		}
+
+		if err := m.remove(ctx, modem, oldAddr, rec.Group, controller); err != nil {
+			return err
+		}
+	}
+
+	if oldErr != nil {
+		return nil
+	}
+
+	return copySettings(ctx, old, m.device(newAddr))
+}
+
+// replacementPeers reads the database of every device records are for, other than the modem and the devices being
+// swapped.
+func (m *LinkManager) replacementPeers(ctx context.Context, records []*AllLinkRecord, skip ...Address) ([]*LinkDatabase,
+	error) {
+	seen := make(map[Address]bool)
+	for _, addr := range skip {
+		seen[addr] = true
+	}
+
+	var peers []*LinkDatabase
+
+	for _, rec := range records {
+		if seen[rec.Address] {
+			continue
+		}
+
+		seen[rec.Address] = true
+
+		db, err := m.device(rec.Address).GetLinkDatabase(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "address: %s", rec.Address)
+		}
+
+		peers = append(peers, db)
+	}
+
+	return peers, nil
+}
+
+// mirrorLinks works out the records a device at addr had from the records the modem and its peers have for it.
+// Responder data isn't kept by controllers, so responder records get the default.
+func mirrorLinks(addr, modem Address, modemRecords []*AllLinkRecord, peers []*LinkDatabase) []*AllLinkRecord {
+	var links []*AllLinkRecord
+
+	mirror := func(owner Address, rec *AllLinkRecord) {
+		if rec.Address != addr {
+			return
+		}
+
+		data := defaultResponderData
+		if !rec.Flags.Controller() {
+			data = deviceControllerData(rec.Group)
+		}
+
+		links = append(links, newLinkRecord(owner, rec.Group, !rec.Flags.Controller(), data))
+	}
+
+	for _, rec := range modemRecords {
+		mirror(modem, rec)
+	}
+
+	for _, db := range peers {
+		for _, r := range deviceAuditRecords(db.Records()) {
+			mirror(db.device.address, r.rec)
+		}
+	}
+
+	return links
+}
+
+// setLink adds rec to db, or updates the data of the record that's already there.
+func setLink(db *LinkDatabase, rec *AllLinkRecord) error {
+	controller := rec.Flags.Controller()
+
+	_, cur, ok := db.Find(rec.Address, rec.Group, controller)
+
+	switch {
+	case !ok:
+		return db.Add(rec.Address, rec.Group, controller, rec.Data)
+	case cur.Data != rec.Data:
+		return db.Update(rec.Address, rec.Group, controller, rec.Data)
+	default:
+		return nil
+	}
+}
+
+// copySettings copies the name and, between dimmers, the ramp rate and on level from one device to another. Settings
+// the old device doesn't have are skipped.
+func copySettings(ctx context.Context, from, to *Device) error {
+	nameCtx, cancel := context.WithTimeout(ctx, replaceSettingsTimeout)
+	name, err := from.GetName(nameCtx)
+
+	cancel()
+
+	switch {
+	case ctx.Err() != nil:
+		return ctx.Err()
+	case err == nil && name != "":
+		if err := to.SetName(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	// The dimmer settings only mean the same thing on another dimmer.
+	for _, d := range []*Device{from, to} {
+		id, err := d.Identify(ctx)
+		if err != nil {
+			return err
+		}
+
+		if Category(id.Category) != CategoryDimmableLighting {
+			return nil
+		}
+	}
+
+	configCtx, cancel := context.WithTimeout(ctx, replaceSettingsTimeout)
+	data, err := from.GetExtendedConfig(configCtx, 0)
+
+	cancel()
+
+	if err != nil {
+		return ctx.Err()
+	}
+
+	if err := to.SetExtendedConfig(ctx, 0, dimmerConfigRampRate, data[dimmerDataRampRate]); err != nil {
+		return err
+	}
+
+	return to.SetExtendedConfig(ctx, 0, dimmerConfigOnLevel, data[dimmerDataOnLevel])
+}
@@ -0,0 +1,179 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type ReplaceTestSuite struct {
+	suite.Suite
+	mock  *InsteonHubMock
+	links *insteon.LinkManager
+}
+
+func (s *ReplaceTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.links = insteon.NewLinkManager(hub)
+}
+
+// expectModem scripts the modem controlling AA.BB.CC with group 1.
+func (s *ReplaceTestSuite) expectModem() {
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x20, 0x45,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xF0},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF0, 0x06,
+			0x02, 0x59, 0x1F, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	)
+}
+
+// expectModemMoved scripts the modem's record of AA.BB.CC moving to 77.88.99.
+func (s *ReplaceTestSuite) expectModemMoved() {
+	s.mock.Expect(
+		[]byte{0x02, 0x6F, 0x40, 0xC2, 0x01, 0x77, 0x88, 0x99, 0x01, 0x20, 0x45},
+		[]byte{0x02, 0x6F, 0x40, 0xC2, 0x01, 0x77, 0x88, 0x99, 0x01, 0x20, 0x45, 0x06},
+		[]byte{0x02, 0x76, 0x1F, 0xF8, 0x62, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x20, 0x45},
+		[]byte{0x02, 0x76, 0x1F, 0xF8, 0x62, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x20, 0x45, 0x06},
+	)
+}
+
+// expectMoved scripts the links of a live AA.BB.CC moving to 77.88.99, followed by its name.
+func (s *ReplaceTestSuite) expectMoved() {
+	old, replacement := insteon.Address{0xAA, 0xBB, 0xCC}, insteon.Address{0x77, 0x88, 0x99}
+	responder := []byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01}
+	copied := []byte{0x0F, 0xFF, 0x82, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01}
+	end := []byte{0x0F, 0xF7, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+	s.expectModem()
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(old, responder)
+	s.mock.ExpectDeviceEngineVersion(replacement, 0x01)
+	s.mock.ExpectDatabase(replacement)
+
+	// The replacement gets the old device's record of the modem, then the modem's record moves.
+	s.mock.ExpectRecordWrite(replacement, end, end)
+	s.mock.ExpectRecordWrite(replacement, copied, copied)
+	s.expectModemMoved()
+
+	// The name is copied.
+	name := []byte{'P', 'o', 'r', 'c', 'h', 0, 0, 0, 0, 0, 0, 0, 0, 0}
+
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x03, 0x02},
+		append([]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x03, 0x02, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x03, 0x02,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x03, 0x02,
+		}, name...),
+	)
+	s.mock.ExpectExtended(replacement, 0x03, 0x02, name)
+}
+
+// expectIdentify scripts the device at addr identifying itself as category cat.
+func (s *ReplaceTestSuite) expectIdentify(addr insteon.Address, cat byte) {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, addr[0], addr[1], addr[2], 0x0F, 0x10, 0x00},
+		[]byte{
+			0x02, 0x62, addr[0], addr[1], addr[2], 0x0F, 0x10, 0x00, 0x06,
+			0x02, 0x50, addr[0], addr[1], addr[2], 0x01, 0x02, 0x03, 0x2B, 0x10, 0x00,
+			0x02, 0x50, addr[0], addr[1], addr[2], cat, 0x20, 0x45, 0x8B, 0x01, 0x00,
+		},
+	)
+}
+
+func (s *ReplaceTestSuite) TestReplaceDevice() {
+	old, replacement := insteon.Address{0xAA, 0xBB, 0xCC}, insteon.Address{0x77, 0x88, 0x99}
+
+	s.expectMoved()
+
+	// Between dimmers, the ramp rate in D7 and the on level in D8 are copied.
+	s.expectIdentify(old, 0x01)
+	s.expectIdentify(replacement, 0x01)
+	s.mock.ExpectExtended(old, 0x2E, 0x00, nil, []byte{
+		0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2E, 0x00,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x1C, 0x7F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	})
+	s.mock.ExpectExtended(replacement, 0x2E, 0x00, []byte{0x00, 0x05, 0x1C})
+	s.mock.ExpectExtended(replacement, 0x2E, 0x00, []byte{0x00, 0x06, 0x7F})
+
+	s.Require().NoError(s.links.ReplaceDevice(s.mock.ctx, old, replacement))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *ReplaceTestSuite) TestReplaceRelayWithDimmer() {
+	old, replacement := insteon.Address{0xAA, 0xBB, 0xCC}, insteon.Address{0x77, 0x88, 0x99}
+
+	// The old device is a relay, so it has no dimmer settings to copy.
+	s.expectMoved()
+	s.expectIdentify(old, 0x02)
+
+	s.Require().NoError(s.links.ReplaceDevice(s.mock.ctx, old, replacement))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *ReplaceTestSuite) TestReplaceDeadDevice() {
+	old, replacement := insteon.Address{0xAA, 0xBB, 0xCC}, insteon.Address{0x77, 0x88, 0x99}
+	peer := insteon.Address{0x44, 0x55, 0x66}
+
+	// The modem also controls a keypad that responds to the old device.
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x01, 0x20, 0x45,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xF0},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF0, 0x06,
+			0x02, 0x59, 0x1F, 0xF0, 0xE2, 0x02, 0x44, 0x55, 0x66, 0x01, 0x1E, 0x41,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xE8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xE8, 0x06,
+			0x02, 0x59, 0x1F, 0xE8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x0D, 0x00},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x0D, 0x00, 0x15},
+	)
+	s.mock.ExpectDeviceEngineVersion(peer, 0x01)
+	s.mock.ExpectDatabase(peer,
+		[]byte{0x0F, 0xFF, 0xA2, 0x02, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01},
+		[]byte{0x0F, 0xF7, 0xA2, 0x01, 0xAA, 0xBB, 0xCC, 0xFF, 0x1F, 0x03},
+	)
+	s.mock.ExpectDeviceEngineVersion(replacement, 0x01)
+	s.mock.ExpectDatabase(replacement)
+
+	// The replacement responds to the modem and controls the keypad, which then points at it.
+	responder := []byte{0x0F, 0xFF, 0x82, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01}
+	controller := []byte{0x0F, 0xF7, 0xC2, 0x01, 0x44, 0x55, 0x66, 0x00, 0x00, 0x01}
+	end := []byte{0x0F, 0xEF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	moved := []byte{0x0F, 0xF7, 0xA2, 0x01, 0x77, 0x88, 0x99, 0xFF, 0x1F, 0x03}
+
+	s.mock.ExpectRecordWrite(replacement, end, end)
+	s.mock.ExpectRecordWrite(replacement, controller, controller)
+	s.mock.ExpectRecordWrite(replacement, responder, responder)
+	s.mock.ExpectRecordWrite(peer, moved, moved)
+	s.expectModemMoved()
+
+	s.Require().NoError(s.links.ReplaceDevice(s.mock.ctx, old, replacement))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func TestReplaceTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(ReplaceTestSuite))
+}
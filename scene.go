@@ -0,0 +1,154 @@
+package insteon
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// ErrSceneExists indicates the modem already controls responders with the scene's group.
+var ErrSceneExists = errors.New("scene already exists")
+
+// SceneResponder is a device that responds to a scene and how it responds.
+type SceneResponder struct {
+	Address  Address
+	OnLevel  byte
+	RampRate byte
+	// Button is the button or channel of the device that responds, 1 for devices with only one.
+	Button byte
+}
+
+func (r *SceneResponder) data() [3]byte {
+	return ResponderData{OnLevel: r.OnLevel, RampRate: r.RampRate, Button: r.Button}.Bytes()
+}
+
+// Scene is a group on the modem that drives a set of responders together, each at its own level and ramp rate. The
+// modem is the controller of each responder for the group and each responder has a record of the modem.
+type Scene struct {
+	Group      byte
+	Responders []*SceneResponder
+
+	links *LinkManager
+}
+
+// NewScene creates a new scene for a group on the modem. Nothing is written until it's created.
+func NewScene(links *LinkManager, group byte, responders ...*SceneResponder) *Scene {
+	return &Scene{Group: group, Responders: responders, links: links}
+}
+
+// ReadScene reads an existing scene back out of the modem's database and the databases of its responders. Responders
+// that have lost their record of the modem are included with a zero on level, ramp rate and button.
+func ReadScene(ctx context.Context, links *LinkManager, group byte) (*Scene, error) {
+	modem, addrs, err := links.sceneResponders(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	scene := NewScene(links, group)
+
+	for _, addr := range addrs {
+		db, err := links.Device(addr).GetLinkDatabase(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		responder := &SceneResponder{Address: addr}
+		if _, rec, ok := db.Find(modem, group, false); ok {
+			data, _ := rec.ResponderData(DeviceOwner(CategoryUnassigned))
+			responder.OnLevel, responder.RampRate, responder.Button = data.OnLevel, data.RampRate, data.Button
+		}
+
+		scene.Responders = append(scene.Responders, responder)
+	}
+
+	return scene, nil
+}
+
+// Create writes the links for a new scene.
+func (s *Scene) Create(ctx context.Context) error {
+	_, addrs, err := s.links.sceneResponders(ctx, s.Group)
+	if err != nil {
+		return err
+	}
+
+	if len(addrs) > 0 {
+		return errors.Wrapf(ErrSceneExists, "group: %d", s.Group)
+	}
+
+	return s.Update(ctx)
+}
+
+// Update writes the links for the scene's responders as they are now, removing the links of responders that are no
+// longer part of it.
+func (s *Scene) Update(ctx context.Context) error {
+	modem, addrs, err := s.links.sceneResponders(ctx, s.Group)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[Address]bool)
+
+	for _, r := range s.Responders {
+		if err := s.links.Link(ctx, modem, r.Address, s.Group, r.data()); err != nil {
+			return err
+		}
+
+		keep[r.Address] = true
+	}
+
+	for _, addr := range addrs {
+		if keep[addr] {
+			continue
+		}
+
+		if err := s.links.Unlink(ctx, modem, addr, s.Group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the links of every responder of the scene.
+func (s *Scene) Delete(ctx context.Context) error {
+	modem, addrs, err := s.links.sceneResponders(ctx, s.Group)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		if err := s.links.Unlink(ctx, modem, addr, s.Group); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Activate turns the scene on, each responder goes to its own on level.
+func (s *Scene) Activate(ctx context.Context) error {
+	return NewGroup(s.links.hub, s.Group).TurnOn(ctx)
+}
+
+// Deactivate turns the scene off.
+func (s *Scene) Deactivate(ctx context.Context) error {
+	return NewGroup(s.links.hub, s.Group).TurnOff(ctx)
+}
+
+// sceneResponders returns the modem's address and the devices it controls with group.
+func (m *LinkManager) sceneResponders(ctx context.Context, group byte) (Address, []Address, error) {
+	modem, records, err := m.modemState(ctx)
+	if err != nil {
+		return Address{}, nil, err
+	}
+
+	var addrs []Address
+
+	for _, rec := range records {
+		if rec.Flags.Controller() && rec.Group == group {
+			addrs = append(addrs, rec.Address)
+		}
+	}
+
+	return modem, addrs, nil
+}
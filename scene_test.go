@@ -0,0 +1,95 @@
+package insteon_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type SceneTestSuite struct {
+	suite.Suite
+	mock  *InsteonHubMock
+	links *insteon.LinkManager
+}
+
+func (s *SceneTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.links = insteon.NewLinkManager(hub)
+}
+
+// expectModem scripts the modem controlling AA.BB.CC with group 20.
+func (s *SceneTestSuite) expectModem() {
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0xE2, 0x14, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xF0},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF0, 0x06,
+			0x02, 0x59, 0x1F, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	)
+}
+
+func (s *SceneTestSuite) TestReadDelete() {
+	device := insteon.Address{0xAA, 0xBB, 0xCC}
+	responder := []byte{0x0F, 0xFF, 0xA2, 0x14, 0x01, 0x02, 0x03, 0x7F, 0x1C, 0x01}
+
+	s.expectModem()
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device, responder)
+
+	scene, err := insteon.ReadScene(s.mock.ctx, s.links, 20)
+	s.Require().NoError(err)
+	s.Require().Equal(byte(20), scene.Group)
+	s.Require().Equal([]*insteon.SceneResponder{
+		{Address: device, OnLevel: 0x7F, RampRate: 0x1C, Button: 0x01},
+	}, scene.Responders)
+
+	// The modem's record is cleared in place, then the device's.
+	deleted := []byte{0x0F, 0xFF, 0x22, 0x14, 0x01, 0x02, 0x03, 0x7F, 0x1C, 0x01}
+
+	s.mock.Expect(
+		[]byte{0x02, 0x76, 0x1F, 0xF8, 0x62, 0x14, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00},
+		[]byte{0x02, 0x76, 0x1F, 0xF8, 0x62, 0x14, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00, 0x06},
+	)
+	s.mock.ExpectDatabase(device, responder)
+	s.mock.ExpectRecordWrite(device, deleted, deleted)
+
+	s.Require().NoError(scene.Delete(s.mock.ctx))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *SceneTestSuite) TestCreateExisting() {
+	s.expectModem()
+
+	scene := insteon.NewScene(s.links, 20, &insteon.SceneResponder{Address: insteon.Address{0x11, 0x22, 0x33}})
+
+	s.Require().ErrorIs(scene.Create(s.mock.ctx), insteon.ErrSceneExists)
+}
+
+func (s *SceneTestSuite) TestActivate() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0x00, 0x00, 0x14, 0xCF, 0x11, 0x00},
+		[]byte{0x02, 0x62, 0x00, 0x00, 0x14, 0xCF, 0x11, 0x00, 0x06},
+		[]byte{0x02, 0x62, 0x00, 0x00, 0x14, 0xCF, 0x13, 0x00},
+		[]byte{0x02, 0x62, 0x00, 0x00, 0x14, 0xCF, 0x13, 0x00, 0x06},
+	)
+
+	scene := insteon.NewScene(s.links, 20)
+	s.Require().NoError(scene.Activate(s.mock.ctx))
+	s.Require().NoError(scene.Deactivate(s.mock.ctx))
+}
+
+func TestSceneSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &SceneTestSuite{})
+}
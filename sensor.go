@@ -0,0 +1,278 @@
+package insteon
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatInterval is how often battery powered sensors send a heartbeat unless configured otherwise.
+const DefaultHeartbeatInterval = 24 * time.Hour
+
+// These are the groups battery powered sensors broadcast on.
+const (
+	sensorGroupOpenClose  byte = 1
+	sensorGroupLeakDry    byte = 1
+	sensorGroupLeakWet    byte = 2
+	sensorGroupLowBattery byte = 3
+	sensorGroupHeartbeat  byte = 4
+)
+
+// SensorEventType describes what happened in a SensorEvent.
+type SensorEventType int
+
+const (
+	SensorEventOpen SensorEventType = iota
+	SensorEventClosed
+	SensorEventWet
+	SensorEventDry
+	SensorEventHeartbeat
+	SensorEventLowBattery
+	// SensorEventMissedHeartbeat is generated when no heartbeat has been received within the heartbeat interval.
+	SensorEventMissedHeartbeat
+
+	sensorEventNone SensorEventType = -1
+)
+
+func (t SensorEventType) String() string {
+	switch t {
+	case SensorEventOpen:
+		return "Open"
+	case SensorEventClosed:
+		return "Closed"
+	case SensorEventWet:
+		return "Wet"
+	case SensorEventDry:
+		return "Dry"
+	case SensorEventHeartbeat:
+		return "Heartbeat"
+	case SensorEventLowBattery:
+		return "Low Battery"
+	case SensorEventMissedHeartbeat:
+		return "Missed Heartbeat"
+	case sensorEventNone:
+		fallthrough
+	default:
+		return "Unknown"
+	}
+}
+
+// SensorEvent is generated when a battery powered sensor broadcasts a change or misses a heartbeat.
+type SensorEvent struct {
+	deviceEvent
+	Type SensorEventType
+	// Time is when the event was received.
+	Time time.Time
+}
+
+// sensorGroup maps the on and off commands broadcast on a group to event types.
+type sensorGroup struct {
+	on  SensorEventType
+	off SensorEventType
+}
+
+// sensor is the common implementation of the battery powered sensors that report state changes and heartbeats.
+type sensor struct {
+	*Device
+	events deviceEvents
+	groups map[byte]sensorGroup
+	// heartbeat maps the on and off commands of a heartbeat to the state they report, nil for sensors whose heartbeat
+	// doesn't carry one.
+	heartbeat *sensorGroup
+
+	mu            sync.Mutex
+	interval      time.Duration
+	lastHeartbeat time.Time
+	timer         *time.Timer
+	state         SensorEventType
+}
+
+func newSensor(hub Hub, addr Address, groups map[byte]sensorGroup) (*sensor, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sensor{Device: dev, groups: groups, interval: DefaultHeartbeatInterval, state: sensorEventNone}, nil
+}
+
+// AddListener registers a listener that's notified with a SensorEvent whenever the sensor broadcasts a change. Once a
+// listener is registered the sensor's heartbeat is monitored and a SensorEventMissedHeartbeat is generated each time
+// the heartbeat interval passes without one.
+func (s *sensor) AddListener(listener DeviceEventListener) {
+	s.events.add(listener)
+	s.events.watch(s.hub, s.address, s.handleMessage)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.interval, s.missedHeartbeat)
+	}
+}
+
+// LastHeartbeat returns when the last heartbeat was received, or the zero time if none has been seen.
+func (s *sensor) LastHeartbeat() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lastHeartbeat
+}
+
+// State returns the last state the sensor reported, such as SensorEventOpen or SensorEventWet, or -1 if it hasn't
+// reported one yet.
+func (s *sensor) State() SensorEventType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// SetHeartbeatInterval changes how long to wait for a heartbeat before reporting it missed. This should be a little
+// longer than the period the sensor is configured to send heartbeats at.
+func (s *sensor) SetHeartbeatInterval(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.interval = interval
+
+	if s.timer != nil {
+		s.timer.Reset(interval)
+	}
+}
+
+// StopHeartbeat stops monitoring the sensor's heartbeat so no more SensorEventMissedHeartbeat events are generated.
+// Registering another listener starts monitoring again.
+func (s *sensor) StopHeartbeat() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+func (s *sensor) missedHeartbeat() {
+	s.mu.Lock()
+	if s.timer == nil {
+		// Monitoring was stopped while the timer was firing.
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.timer.Reset(s.interval)
+	s.mu.Unlock()
+
+	s.events.emit(&SensorEvent{
+		deviceEvent: deviceEvent{from: s.address},
+		Type:        SensorEventMissedHeartbeat,
+		Time:        time.Now(),
+	})
+}
+
+func (s *sensor) handleMessage(rsp CommandResponse) {
+	group, ok := groupBroadcast(rsp)
+	if !ok {
+		return
+	}
+
+	mapping, ok := s.groups[group]
+	if !ok {
+		return
+	}
+
+	evt := &SensorEvent{deviceEvent: deviceEvent{from: s.address}, Time: time.Now()}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn:
+		evt.Type = mapping.on
+	case cmdControlOff:
+		evt.Type = mapping.off
+	default:
+		return
+	}
+
+	if evt.Type == sensorEventNone {
+		return
+	}
+
+	switch evt.Type {
+	case SensorEventHeartbeat:
+		s.mu.Lock()
+		s.lastHeartbeat = evt.Time
+
+		if s.timer != nil {
+			s.timer.Reset(s.interval)
+		}
+		s.mu.Unlock()
+
+		if s.heartbeat != nil {
+			// The heartbeat repeats the sensor's state, which corrects a state broadcast that was missed.
+			state := &SensorEvent{deviceEvent: evt.deviceEvent, Type: s.heartbeat.on, Time: evt.Time}
+			if rsp.Cmd1() == cmdControlOff {
+				state.Type = s.heartbeat.off
+			}
+
+			if s.setState(state.Type) {
+				s.events.emit(state)
+			}
+		}
+	case SensorEventOpen, SensorEventClosed, SensorEventWet, SensorEventDry:
+		s.setState(evt.Type)
+	}
+
+	s.events.emit(evt)
+}
+
+// setState records the state the sensor reported, returning true if it changed.
+func (s *sensor) setState(state SensorEventType) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := s.state != state
+	s.state = state
+
+	return changed
+}
+
+// OpenCloseSensor represents a door or window sensor such as the TriggerLinc [2421] or the hidden door sensor [2845].
+type OpenCloseSensor struct {
+	*sensor
+}
+
+// NewOpenCloseSensor creates a new open/close sensor by raw address.
+func NewOpenCloseSensor(hub Hub, addr Address) (*OpenCloseSensor, error) {
+	s, err := newSensor(hub, addr, map[byte]sensorGroup{
+		sensorGroupOpenClose:  {on: SensorEventOpen, off: SensorEventClosed},
+		sensorGroupLowBattery: {on: SensorEventLowBattery, off: sensorEventNone},
+		sensorGroupHeartbeat:  {on: SensorEventHeartbeat, off: SensorEventHeartbeat},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenCloseSensor{sensor: s}, nil
+}
+
+// LeakSensor represents a water leak sensor such as the 2852.
+type LeakSensor struct {
+	*sensor
+}
+
+// NewLeakSensor creates a new leak sensor by raw address.
+func NewLeakSensor(hub Hub, addr Address) (*LeakSensor, error) {
+	s, err := newSensor(hub, addr, map[byte]sensorGroup{
+		sensorGroupLeakDry:   {on: SensorEventDry, off: sensorEventNone},
+		sensorGroupLeakWet:   {on: SensorEventWet, off: sensorEventNone},
+		sensorGroupHeartbeat: {on: SensorEventHeartbeat, off: SensorEventHeartbeat},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The leak sensor's heartbeat is an on command while it's dry and an off command while it's wet.
+	s.heartbeat = &sensorGroup{on: SensorEventDry, off: SensorEventWet}
+
+	return &LeakSensor{sensor: s}, nil
+}
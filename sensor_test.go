@@ -0,0 +1,121 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type SensorTestSuite struct {
+	suite.Suite
+	mock *InsteonHubMock
+	hub  insteon.Hub
+}
+
+func (s *SensorTestSuite) SetupTest() {
+	s.hub, s.mock = newMock()
+}
+
+func (s *SensorTestSuite) TestLeakSensor() {
+	sensor, err := insteon.NewLeakSensor(s.hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+
+	events := make(chan *insteon.SensorEvent, 2)
+
+	sensor.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.SensorEvent)
+	})
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x02, 0xCF, 0x11, 0x00})
+	}()
+
+	var evt *insteon.SensorEvent
+
+	select {
+	case evt = <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Equal(insteon.SensorEventWet, evt.Type)
+	s.Require().True(sensor.LastHeartbeat().IsZero())
+
+	s.Require().Equal(insteon.SensorEventWet, sensor.State())
+
+	// A heartbeat that agrees with the state is only a heartbeat.
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x04, 0xCF, 0x13, 0x00})
+	}()
+
+	select {
+	case evt = <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Equal(insteon.SensorEventHeartbeat, evt.Type)
+	s.Require().Equal(evt.Time, sensor.LastHeartbeat())
+
+	// The broadcast that the sensor dried out was missed, the next heartbeat corrects the state.
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x04, 0xCF, 0x11, 0x00})
+	}()
+
+	for _, want := range []insteon.SensorEventType{insteon.SensorEventDry, insteon.SensorEventHeartbeat} {
+		select {
+		case evt = <-events:
+		case <-time.After(time.Second):
+			s.FailNow("event wasn't reported")
+		}
+
+		s.Require().Equal(want, evt.Type)
+	}
+
+	s.Require().Equal(insteon.SensorEventDry, sensor.State())
+}
+
+func (s *SensorTestSuite) TestMissedHeartbeat() {
+	sensor, err := insteon.NewOpenCloseSensor(s.hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+
+	sensor.SetHeartbeatInterval(50 * time.Millisecond)
+
+	events := make(chan *insteon.SensorEvent, 1)
+
+	sensor.AddListener(func(evt insteon.DeviceEvent) {
+		select {
+		case events <- evt.(*insteon.SensorEvent):
+		default:
+		}
+	})
+
+	select {
+	case evt := <-events:
+		s.Require().Equal(insteon.SensorEventMissedHeartbeat, evt.Type)
+	case <-time.After(time.Second):
+		s.Fail("missed heartbeat wasn't reported")
+	}
+
+	sensor.StopHeartbeat()
+
+	// Drain anything that fired while stopping.
+	select {
+	case <-events:
+	default:
+	}
+
+	select {
+	case evt := <-events:
+		s.Failf("missed heartbeat reported after stopping", "event: %s", evt.Type)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestSensorSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &SensorTestSuite{})
+}
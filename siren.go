@@ -0,0 +1,196 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// These are the groups a siren is operated and reports on.
+const (
+	sirenGroupSound byte = 1
+	sirenGroupArm   byte = 2
+)
+
+// These are the extended configuration settings of a siren and the offsets of their current values in the extended
+// configuration data.
+const (
+	sirenConfigDuration  byte = 0x06
+	sirenConfigAlertType byte = 0x07
+
+	sirenDataArmed     = 2
+	sirenDataDuration  = 3
+	sirenDataAlertType = 4
+)
+
+// SirenAlertType is the sound a siren makes when it goes off.
+type SirenAlertType byte
+
+const (
+	SirenAlertTypeSiren SirenAlertType = 0x00
+	SirenAlertTypeChime SirenAlertType = 0x01
+	SirenAlertTypeBeep  SirenAlertType = 0x02
+)
+
+func (t SirenAlertType) String() string {
+	switch t {
+	case SirenAlertTypeSiren:
+		return "Siren"
+	case SirenAlertTypeChime:
+		return "Chime"
+	case SirenAlertTypeBeep:
+		return "Beep"
+	default:
+		return "Unknown"
+	}
+}
+
+// SirenConfig is the current configuration of a siren.
+type SirenConfig struct {
+	Armed bool
+	// Duration is how long the siren sounds for once triggered.
+	Duration  time.Duration
+	AlertType SirenAlertType
+}
+
+func (c *SirenConfig) String() string {
+	return fmt.Sprintf("Armed=%t, Duration=%s, AlertType=%s", c.Armed, c.Duration, c.AlertType)
+}
+
+// SirenEventType describes what changed in a SirenEvent.
+type SirenEventType int
+
+const (
+	// SirenEventSounding is generated when the siren starts (On) or stops (Off) sounding.
+	SirenEventSounding SirenEventType = iota
+	// SirenEventArmed is generated when the siren is armed (On) or disarmed (Off).
+	SirenEventArmed
+)
+
+func (t SirenEventType) String() string {
+	switch t {
+	case SirenEventSounding:
+		return "Sounding"
+	case SirenEventArmed:
+		return "Armed"
+	default:
+		return "Unknown"
+	}
+}
+
+// SirenEvent is generated when a siren reports a change.
+type SirenEvent struct {
+	deviceEvent
+	Type SirenEventType
+	On   bool
+}
+
+// Siren represents a siren and alert module such as the 2868. Group 1 sounds the siren, group 2 arms it.
+type Siren struct {
+	*Device
+	events deviceEvents
+}
+
+// NewSiren creates a new siren by raw address.
+func NewSiren(hub Hub, addr Address) (*Siren, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Siren{Device: dev}, nil
+}
+
+// Sound sets the siren off.
+func (s *Siren) Sound(ctx context.Context) error {
+	_, err := s.sendMessage(ctx, cmdControlOn, 0xFF)
+
+	return err
+}
+
+// Silence stops the siren.
+func (s *Siren) Silence(ctx context.Context) error {
+	_, err := s.sendMessage(ctx, cmdControlOff, 0)
+
+	return err
+}
+
+// Arm arms the siren so it sounds when triggered by a linked sensor.
+func (s *Siren) Arm(ctx context.Context) error {
+	_, err := s.sendExtended(ctx, cmdControlOn, 0xFF, [14]byte{sirenGroupArm})
+
+	return err
+}
+
+// Disarm disarms the siren.
+func (s *Siren) Disarm(ctx context.Context) error {
+	_, err := s.sendExtended(ctx, cmdControlOff, 0, [14]byte{sirenGroupArm})
+
+	return err
+}
+
+// GetConfig reads the siren's armed state, sound duration and alert type.
+func (s *Siren) GetConfig(ctx context.Context) (*SirenConfig, error) {
+	data, err := s.GetExtendedConfig(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SirenConfig{
+		Armed:     data[sirenDataArmed] > 0,
+		Duration:  time.Duration(data[sirenDataDuration]) * time.Second,
+		AlertType: SirenAlertType(data[sirenDataAlertType]),
+	}, nil
+}
+
+// SetDuration sets how long the siren sounds for once triggered, between 1 and 255 seconds.
+func (s *Siren) SetDuration(ctx context.Context, duration time.Duration) error {
+	const maxSeconds = 0xFF
+
+	seconds := duration / time.Second
+	if seconds < 1 || seconds > maxSeconds {
+		return fmt.Errorf("siren duration out of range: %s", duration)
+	}
+
+	return s.SetExtendedConfig(ctx, 0, sirenConfigDuration, byte(seconds))
+}
+
+// SetAlertType sets the sound the siren makes when it goes off.
+func (s *Siren) SetAlertType(ctx context.Context, alertType SirenAlertType) error {
+	return s.SetExtendedConfig(ctx, 0, sirenConfigAlertType, byte(alertType))
+}
+
+// AddListener registers a listener that's notified with a SirenEvent whenever the siren sounds or is armed.
+func (s *Siren) AddListener(listener DeviceEventListener) {
+	s.events.add(listener)
+	s.events.watch(s.hub, s.address, s.handleMessage)
+}
+
+func (s *Siren) handleMessage(rsp CommandResponse) {
+	group, ok := groupBroadcast(rsp)
+	if !ok {
+		return
+	}
+
+	evt := &SirenEvent{deviceEvent: deviceEvent{from: s.address}}
+
+	switch group {
+	case sirenGroupSound:
+		evt.Type = SirenEventSounding
+	case sirenGroupArm:
+		evt.Type = SirenEventArmed
+	default:
+		return
+	}
+
+	switch rsp.Cmd1() {
+	case cmdControlOn, cmdControlFastOn:
+		evt.On = true
+	case cmdControlOff, cmdControlFastOff:
+		evt.On = false
+	default:
+		return
+	}
+
+	s.events.emit(evt)
+}
@@ -0,0 +1,81 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type SirenTestSuite struct {
+	suite.Suite
+	mock  *InsteonHubMock
+	siren *insteon.Siren
+}
+
+func (s *SirenTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.siren, err = insteon.NewSiren(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *SirenTestSuite) TestArm() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x11, 0xFF, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xEC},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x11, 0xFF, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xEC, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x11, 0xFF,
+		},
+	)
+
+	s.Require().NoError(s.siren.Arm(s.mock.ctx))
+}
+
+func (s *SirenTestSuite) TestEvents() {
+	events := make(chan *insteon.SirenEvent, 1)
+
+	s.siren.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.SirenEvent)
+	})
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x02, 0xCF, 0x13, 0x00})
+	}()
+
+	var evt *insteon.SirenEvent
+
+	select {
+	case evt = <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Equal(insteon.SirenEventArmed, evt.Type)
+	s.Require().False(evt.On)
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x01, 0xCF, 0x11, 0xFF})
+	}()
+
+	select {
+	case evt = <-events:
+	case <-time.After(time.Second):
+		s.FailNow("event wasn't reported")
+	}
+
+	s.Require().Equal(insteon.SirenEventSounding, evt.Type)
+	s.Require().True(evt.On)
+}
+
+func TestSirenSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &SirenTestSuite{})
+}
@@ -0,0 +1,505 @@
+package insteon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SnapshotVersion is the version of the snapshot format written by this package.
+const SnapshotVersion = 1
+
+// ErrSnapshotVersion indicates a snapshot was written in a format this package doesn't understand.
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+// Snapshot is a copy of the link databases and settings of the modem and a set of devices, taken so they can be put
+// back after a risky change or onto replacement hardware. It's written and read as JSON.
+type Snapshot struct {
+	Version int
+	Taken   time.Time
+	Modem   *ModemSnapshot
+	Devices []*DeviceSnapshot
+}
+
+// ModemSnapshot is a copy of the modem's database.
+type ModemSnapshot struct {
+	Address Address
+	Links   []*AllLinkRecord
+}
+
+// DeviceSnapshot is a copy of a device's database and settings. Operating flags and extended configuration mean
+// something different to each kind of device, Restore only puts back the settings of dimmers and relays: their
+// operating flags and, for dimmers, the ramp rate and on level. Other devices' settings are compared by Diff but left
+// behind by Restore.
+type DeviceSnapshot struct {
+	Address Address
+	// Category and SubCategory are what the device identified itself as.
+	Category       Category
+	SubCategory    SubCategory
+	OperatingFlags DeviceOpFlags
+	// ExtendedConfig is nil for devices that don't have any.
+	ExtendedConfig *[14]byte `json:",omitempty"`
+	Links          []*AllLinkRecord
+}
+
+// TakeSnapshot takes a snapshot of the modem and devices. It fails if any of them can't be read, sleeping battery
+// devices have to be woken up first.
+func TakeSnapshot(ctx context.Context, links *LinkManager, devices ...Address) (*Snapshot, error) {
+	snap := &Snapshot{Version: SnapshotVersion, Taken: time.Now()}
+
+	modem, err := SnapshotModem(ctx, links)
+	if err != nil {
+		return nil, err
+	}
+
+	snap.Modem = modem
+
+	for _, addr := range devices {
+		dev, err := SnapshotDevice(ctx, links.Device(addr))
+		if err != nil {
+			return nil, errors.Wrapf(err, "address: %s", addr)
+		}
+
+		snap.Devices = append(snap.Devices, dev)
+	}
+
+	return snap, nil
+}
+
+// SnapshotModem takes a snapshot of the modem's database.
+func SnapshotModem(ctx context.Context, links *LinkManager) (*ModemSnapshot, error) {
+	modem, records, err := links.reloadModem(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &ModemSnapshot{Address: modem}
+	for _, r := range records {
+		snap.Links = append(snap.Links, r.rec)
+	}
+
+	return snap, nil
+}
+
+// SnapshotDevice takes a snapshot of a device's database and settings.
+func SnapshotDevice(ctx context.Context, d *Device) (*DeviceSnapshot, error) {
+	records, err := d.GetDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &DeviceSnapshot{Address: d.address}
+	for _, r := range deviceAuditRecords(records) {
+		snap.Links = append(snap.Links, r.rec)
+	}
+
+	id, err := d.Identify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap.Category, snap.SubCategory = Category(id.Category), SubCategory(id.SubCategory)
+
+	if snap.OperatingFlags, err = d.GetOperatingFlags(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := d.GetExtendedConfig(ctx, 0)
+
+	switch {
+	case err == nil:
+		snap.ExtendedConfig = &data
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	}
+
+	return snap, nil
+}
+
+// ReadSnapshot reads a snapshot written by Write.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	snap := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(snap); err != nil {
+		return nil, err
+	}
+
+	if snap.Version != SnapshotVersion {
+		return nil, errors.Wrapf(ErrSnapshotVersion, "version: %d", snap.Version)
+	}
+
+	return snap, nil
+}
+
+// Write writes the snapshot as JSON.
+func (s *Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(s)
+}
+
+// Restore puts the modem's database and the database and settings of every device in the snapshot back the way they
+// were, as far as DeviceSnapshot.Restore can.
+func (s *Snapshot) Restore(ctx context.Context, links *LinkManager) error {
+	if s.Modem != nil {
+		if err := s.Modem.Restore(ctx, links); err != nil {
+			return err
+		}
+	}
+
+	for _, dev := range s.Devices {
+		if err := dev.Restore(ctx, links, dev.Address); err != nil {
+			return errors.Wrapf(err, "address: %s", dev.Address)
+		}
+	}
+
+	return nil
+}
+
+// Restore writes the links of the snapshot to the modem, which needn't be the one it was taken from. Only the records
+// that differ are written: links the modem has that aren't in the snapshot are deleted, then the rest are added or
+// updated.
+func (s *ModemSnapshot) Restore(ctx context.Context, links *LinkManager) error {
+	if _, _, err := links.reloadModem(ctx); err != nil {
+		return err
+	}
+
+	links.mu.Lock()
+	defer links.mu.Unlock()
+
+	modem, err := links.modemAddress(ctx)
+	if err != nil {
+		return err
+	}
+
+	db, err := links.modemLinkDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	want := snapshotLinks(s.Links)
+
+	// Deleting first makes room for the additions.
+	for _, rec := range db.Records() {
+		if _, ok := want[linkKey(rec)]; !ok {
+			if err := links.remove(ctx, modem, rec.Address, rec.Group, rec.Flags.Controller()); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rec := range uniqueLinks(s.Links) {
+		if _, err := links.ensure(ctx, modem, rec.Address, rec.Group, rec.Flags.Controller(), rec.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Restore writes the links of the snapshot to the device at addr, which is either the device the snapshot was taken
+// from or its replacement. All the changes are applied together and only the records that differ are written. The
+// settings of a dimmer or relay are then put back if the device at addr is the same kind, again only those that
+// differ. The settings of other devices are left as they are.
+func (s *DeviceSnapshot) Restore(ctx context.Context, links *LinkManager, addr Address) error {
+	dev := links.Device(addr)
+
+	db, err := dev.GetLinkDatabase(ctx)
+	if err != nil {
+		return err
+	}
+
+	want := snapshotLinks(s.Links)
+
+	for _, r := range deviceAuditRecords(db.Records()) {
+		if _, ok := want[linkKey(r.rec)]; !ok {
+			if err := db.Delete(r.rec.Address, r.rec.Group, r.rec.Flags.Controller()); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rec := range uniqueLinks(s.Links) {
+		controller := rec.Flags.Controller()
+
+		_, cur, ok := db.Find(rec.Address, rec.Group, controller)
+
+		switch {
+		case !ok:
+			err = db.Add(rec.Address, rec.Group, controller, rec.Data)
+		case cur.Data != rec.Data:
+			err = db.Update(rec.Address, rec.Group, controller, rec.Data)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := db.Apply(ctx); err != nil {
+		return err
+	}
+
+	return s.restoreSettings(ctx, dev)
+}
+
+// lightingOpFlag is an operating flag of dimmers and relays, along with the commands that set and clear it.
+type lightingOpFlag struct {
+	mask  DeviceOpFlags
+	set   byte
+	clear byte
+}
+
+// lightingOpFlags are the operating flags of dimmers and relays that Restore puts back.
+var lightingOpFlags = []lightingOpFlag{
+	{mask: 0x01, set: 0x00, clear: 0x01}, // Program lock
+	{mask: 0x02, set: 0x02, clear: 0x03}, // LED on transmit
+	{mask: 0x04, set: 0x04, clear: 0x05}, // Resume dim
+	{mask: 0x10, set: 0x08, clear: 0x09}, // LED off
+}
+
+// restoreSettings puts back the operating flags of a dimmer or relay and the ramp rate and on level of a dimmer.
+func (s *DeviceSnapshot) restoreSettings(ctx context.Context, d *Device) error {
+	if s.Category != CategoryDimmableLighting && s.Category != CategorySwitchedLighting {
+		return nil
+	}
+
+	id, err := d.Identify(ctx)
+	if err != nil {
+		return err
+	}
+
+	if Category(id.Category) != s.Category {
+		return nil
+	}
+
+	flags, err := d.GetOperatingFlags(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range lightingOpFlags {
+		if (flags^s.OperatingFlags)&f.mask == 0 {
+			continue
+		}
+
+		cmd := f.clear
+		if s.OperatingFlags&f.mask != 0 {
+			cmd = f.set
+		}
+
+		if err := d.setOperatingFlag(ctx, cmd); err != nil {
+			return err
+		}
+	}
+
+	if s.Category != CategoryDimmableLighting || s.ExtendedConfig == nil {
+		return nil
+	}
+
+	data, err := d.GetExtendedConfig(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, setting := range []struct {
+		config byte
+		offset int
+	}{
+		{dimmerConfigRampRate, dimmerDataRampRate},
+		{dimmerConfigOnLevel, dimmerDataOnLevel},
+	} {
+		if want := s.ExtendedConfig[setting.offset]; data[setting.offset] != want {
+			if err := d.SetExtendedConfig(ctx, 0, setting.config, want); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// LinkChange is a link that differs between two snapshots.
+type LinkChange struct {
+	// Owner is the device, or modem, whose database holds the link.
+	Owner Address
+	// Kind is what sort of owner it is, which decides how the records read.
+	Kind LinkOwner
+	// Old is nil for links that were added and New is nil for links that were removed.
+	Old *AllLinkRecord
+	New *AllLinkRecord
+}
+
+func (c *LinkChange) String() string {
+	switch {
+	case c.Old == nil:
+		return fmt.Sprintf("%s: added %s", c.Owner, c.New.Format(c.Kind))
+	case c.New == nil:
+		return fmt.Sprintf("%s: removed %s", c.Owner, c.Old.Format(c.Kind))
+	default:
+		return fmt.Sprintf("%s: changed %s to %s", c.Owner, c.Old.Format(c.Kind), c.New.Format(c.Kind))
+	}
+}
+
+// SettingChange is a device setting that differs between two snapshots.
+type SettingChange struct {
+	Owner Address
+	// Setting is the name of the setting, OperatingFlags or ExtendedConfig.
+	Setting string
+	Old     string
+	New     string
+}
+
+func (c *SettingChange) String() string {
+	return fmt.Sprintf("%s: %s changed from %s to %s", c.Owner, c.Setting, c.Old, c.New)
+}
+
+// SnapshotDiff is the difference between two snapshots.
+type SnapshotDiff struct {
+	Links    []*LinkChange
+	Settings []*SettingChange
+}
+
+// Empty returns true if the snapshots are the same.
+func (d *SnapshotDiff) Empty() bool {
+	return len(d.Links) == 0 && len(d.Settings) == 0
+}
+
+// Diff returns what changed from s to other. The modems of the two snapshots are compared with each other even if
+// their addresses differ, devices are matched by address. Links are matched by address, group and role, so records
+// that only moved within a database aren't changes.
+func (s *Snapshot) Diff(other *Snapshot) *SnapshotDiff {
+	diff := &SnapshotDiff{}
+
+	var oldModem, newModem ModemSnapshot
+	if s.Modem != nil {
+		oldModem = *s.Modem
+	}
+
+	if other.Modem != nil {
+		newModem = *other.Modem
+	}
+
+	owner := newModem.Address
+	if other.Modem == nil {
+		owner = oldModem.Address
+	}
+
+	diff.Links = append(diff.Links, diffLinks(owner, ModemOwner, oldModem.Links, newModem.Links)...)
+
+	devices := make(map[Address]*DeviceSnapshot, len(s.Devices))
+	for _, dev := range s.Devices {
+		devices[dev.Address] = dev
+	}
+
+	for _, dev := range other.Devices {
+		old, ok := devices[dev.Address]
+		if !ok {
+			old = &DeviceSnapshot{Address: dev.Address, OperatingFlags: dev.OperatingFlags,
+				ExtendedConfig: dev.ExtendedConfig}
+		}
+
+		delete(devices, dev.Address)
+		diff.diffDevice(old, dev)
+	}
+
+	// What's left are devices that are no longer in the snapshot.
+	for _, dev := range s.Devices {
+		if _, ok := devices[dev.Address]; ok {
+			diff.Links = append(diff.Links, diffLinks(dev.Address, DeviceOwner(dev.Category), dev.Links, nil)...)
+		}
+	}
+
+	return diff
+}
+
+func (d *SnapshotDiff) diffDevice(old, cur *DeviceSnapshot) {
+	d.Links = append(d.Links, diffLinks(cur.Address, DeviceOwner(cur.Category), old.Links, cur.Links)...)
+
+	if old.OperatingFlags != cur.OperatingFlags {
+		d.Settings = append(d.Settings, &SettingChange{
+			Owner:   cur.Address,
+			Setting: "OperatingFlags",
+			Old:     fmt.Sprintf("%02X", byte(old.OperatingFlags)),
+			New:     fmt.Sprintf("%02X", byte(cur.OperatingFlags)),
+		})
+	}
+
+	oldConfig, newConfig := configString(old.ExtendedConfig), configString(cur.ExtendedConfig)
+	if oldConfig != newConfig {
+		d.Settings = append(d.Settings, &SettingChange{
+			Owner:   cur.Address,
+			Setting: "ExtendedConfig",
+			Old:     oldConfig,
+			New:     newConfig,
+		})
+	}
+}
+
+// diffLinks returns the links of owner, which is of kind, that were removed, changed or added going from old to cur.
+func diffLinks(owner Address, kind LinkOwner, old, cur []*AllLinkRecord) []*LinkChange {
+	var changes []*LinkChange
+
+	had, want := snapshotLinks(old), snapshotLinks(cur)
+
+	for _, rec := range uniqueLinks(old) {
+		if next, ok := want[linkKey(rec)]; !ok {
+			changes = append(changes, &LinkChange{Owner: owner, Kind: kind, Old: rec})
+		} else if next.Data != rec.Data {
+			changes = append(changes, &LinkChange{Owner: owner, Kind: kind, Old: rec, New: next})
+		}
+	}
+
+	for _, rec := range uniqueLinks(cur) {
+		if _, ok := had[linkKey(rec)]; !ok {
+			changes = append(changes, &LinkChange{Owner: owner, Kind: kind, New: rec})
+		}
+	}
+
+	return changes
+}
+
+// linkKey returns the address, group and role a record is for.
+func linkKey(rec *AllLinkRecord) auditKey {
+	return auditKey{rec.Address, rec.Group, rec.Flags.Controller()}
+}
+
+// uniqueLinks returns the records in order, leaving out any that repeat an earlier one.
+func uniqueLinks(recs []*AllLinkRecord) []*AllLinkRecord {
+	var unique []*AllLinkRecord
+
+	seen := make(map[auditKey]bool, len(recs))
+
+	for _, rec := range recs {
+		if key := linkKey(rec); !seen[key] {
+			seen[key] = true
+			unique = append(unique, rec)
+		}
+	}
+
+	return unique
+}
+
+// snapshotLinks indexes records by the address, group and role they're for, the first of any repeats wins.
+func snapshotLinks(recs []*AllLinkRecord) map[auditKey]*AllLinkRecord {
+	links := make(map[auditKey]*AllLinkRecord, len(recs))
+
+	for _, rec := range uniqueLinks(recs) {
+		links[linkKey(rec)] = rec
+	}
+
+	return links
+}
+
+// configString formats extended configuration for a diff.
+func configString(data *[14]byte) string {
+	if data == nil {
+		return "none"
+	}
+
+	return fmt.Sprintf("%02X", data[:])
+}
@@ -0,0 +1,254 @@
+package insteon_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type SnapshotTestSuite struct {
+	suite.Suite
+	mock  *InsteonHubMock
+	links *insteon.LinkManager
+}
+
+func (s *SnapshotTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+	s.links = insteon.NewLinkManager(hub)
+}
+
+// expectIdentify scripts AA.BB.CC identifying itself as a device of category cat.
+func (s *SnapshotTestSuite) expectIdentify(cat byte) {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x10, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x10, 0x00,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, cat, 0x20, 0x45, 0x8B, 0x01, 0x00,
+		},
+	)
+}
+
+func (s *SnapshotTestSuite) TestTakeSnapshot() {
+	modem, device := insteon.Address{0x01, 0x02, 0x03}, insteon.Address{0xAA, 0xBB, 0xCC}
+
+	s.mock.Expect(
+		[]byte{0x02, 0x60},
+		[]byte{0x02, 0x60, 0x01, 0x02, 0x03, 0x03, 0x37, 0x9c, 0x06},
+		[]byte{0x02, 0x75, 0x1F, 0xF8},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF8, 0x06,
+			0x02, 0x59, 0x1F, 0xF8, 0xE2, 0x01, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x00,
+		},
+		[]byte{0x02, 0x75, 0x1F, 0xF0},
+		[]byte{
+			0x02, 0x75, 0x1F, 0xF0, 0x06,
+			0x02, 0x59, 0x1F, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		},
+	)
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device, []byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01})
+	s.expectIdentify(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x1F, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x1F, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x1F, 0x10,
+		},
+	)
+	s.mock.ExpectExtended(device, 0x2E, 0x00, nil, []byte{
+		0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2E, 0x00,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x1C, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	})
+
+	snap, err := insteon.TakeSnapshot(s.mock.ctx, s.links, device)
+	s.Require().NoError(err)
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+
+	s.Require().Equal(&insteon.ModemSnapshot{
+		Address: modem,
+		Links:   []*insteon.AllLinkRecord{{Flags: 0xE2, Group: 1, Address: device}},
+	}, snap.Modem)
+	s.Require().Equal([]*insteon.DeviceSnapshot{{
+		Address:        device,
+		Category:       insteon.CategoryDimmableLighting,
+		SubCategory:    0x20,
+		OperatingFlags: 0x10,
+		ExtendedConfig: &[14]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x1C, 0xFF},
+		Links: []*insteon.AllLinkRecord{
+			{Flags: 0xA2, Group: 1, Address: modem, Data: [3]byte{0xFF, 0x1C, 0x01}},
+		},
+	}}, snap.Devices)
+
+	// The snapshot reads back the same as it was written.
+	var buf bytes.Buffer
+
+	s.Require().NoError(snap.Write(&buf))
+	s.Require().Contains(buf.String(), `"Address": "AA:BB:CC"`)
+
+	read, err := insteon.ReadSnapshot(&buf)
+	s.Require().NoError(err)
+	s.Require().True(snap.Taken.Equal(read.Taken))
+	s.Require().Equal(snap.Modem, read.Modem)
+	s.Require().Equal(snap.Devices, read.Devices)
+	s.Require().True(snap.Diff(read).Empty())
+}
+
+func (s *SnapshotTestSuite) TestReadSnapshotVersion() {
+	_, err := insteon.ReadSnapshot(bytes.NewBufferString(`{"Version": 2}`))
+	s.Require().ErrorIs(err, insteon.ErrSnapshotVersion)
+
+	_, err = insteon.ReadSnapshot(bytes.NewBufferString(`{"Version": 1, "Modem": {"Address": "AA:BB"}}`))
+	s.Require().ErrorIs(err, insteon.ErrAddressFormat)
+}
+
+func (s *SnapshotTestSuite) TestRestoreDevice() {
+	modem, device := insteon.Address{0x01, 0x02, 0x03}, insteon.Address{0xAA, 0xBB, 0xCC}
+	snap := &insteon.DeviceSnapshot{
+		Address: device,
+		Links: []*insteon.AllLinkRecord{
+			{Flags: 0xA2, Group: 1, Address: modem, Data: [3]byte{0xFF, 0x1C, 0x01}},
+			{Flags: 0xE2, Group: 1, Address: modem, Data: [3]byte{0x00, 0x00, 0x01}},
+		},
+	}
+
+	// The responder only needs new data, the link to another device goes and the controller takes its place.
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device,
+		[]byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0x7F, 0x1C, 0x01},
+		[]byte{0x0F, 0xF7, 0xE2, 0x03, 0x99, 0x88, 0x77, 0x00, 0x00, 0x03},
+	)
+
+	controller := []byte{0x0F, 0xF7, 0xC2, 0x01, 0x01, 0x02, 0x03, 0x00, 0x00, 0x01}
+	responder := []byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01}
+
+	s.mock.ExpectRecordWrite(device, controller, controller)
+	s.mock.ExpectRecordWrite(device, responder, responder)
+
+	s.Require().NoError(snap.Restore(s.mock.ctx, s.links, device))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *SnapshotTestSuite) TestRestoreDeviceSettings() {
+	modem, device := insteon.Address{0x01, 0x02, 0x03}, insteon.Address{0xAA, 0xBB, 0xCC}
+	responder := []byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01}
+	config := [14]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x1C, 0x7F}
+	snap := &insteon.DeviceSnapshot{
+		Address:        device,
+		Category:       insteon.CategoryDimmableLighting,
+		OperatingFlags: 0x05,
+		ExtendedConfig: &config,
+		Links:          []*insteon.AllLinkRecord{{Flags: 0xA2, Group: 1, Address: modem, Data: [3]byte{0xFF, 0x1C, 0x01}}},
+	}
+
+	// The links are already right. Program lock is set and the LED turned back on, resume dim is left alone.
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device, responder)
+	s.expectIdentify(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x1F, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x1F, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x1F, 0x14,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x20, 0x00},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x20, 0x00, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x20, 0x00,
+		},
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x20, 0x09},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x20, 0x09, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x20, 0x09,
+		},
+	)
+
+	// Only the on level differs.
+	s.mock.ExpectExtended(device, 0x2E, 0x00, nil, []byte{
+		0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2E, 0x00,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x1C, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	})
+	s.mock.ExpectExtended(device, 0x2E, 0x00, []byte{0x00, 0x06, 0x7F})
+
+	s.Require().NoError(snap.Restore(s.mock.ctx, s.links, device))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *SnapshotTestSuite) TestRestoreDeviceOtherKind() {
+	modem, device := insteon.Address{0x01, 0x02, 0x03}, insteon.Address{0xAA, 0xBB, 0xCC}
+	snap := &insteon.DeviceSnapshot{
+		Address:        device,
+		Category:       insteon.CategoryDimmableLighting,
+		OperatingFlags: 0x05,
+		Links:          []*insteon.AllLinkRecord{{Flags: 0xA2, Group: 1, Address: modem, Data: [3]byte{0xFF, 0x1C, 0x01}}},
+	}
+
+	// A dimmer's settings aren't put on a relay.
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.ExpectDatabase(device, []byte{0x0F, 0xFF, 0xA2, 0x01, 0x01, 0x02, 0x03, 0xFF, 0x1C, 0x01})
+	s.expectIdentify(0x02)
+
+	s.Require().NoError(snap.Restore(s.mock.ctx, s.links, device))
+	s.Require().Equal(0, s.mock.inBuffer.Len())
+}
+
+func (s *SnapshotTestSuite) TestDiff() {
+	oldModem, newModem := insteon.Address{0x01, 0x02, 0x03}, insteon.Address{0x04, 0x05, 0x06}
+	device, gone := insteon.Address{0xAA, 0xBB, 0xCC}, insteon.Address{0x99, 0x88, 0x77}
+	config := [14]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x1C}
+	dimmer := insteon.DeviceOwner(insteon.CategoryDimmableLighting)
+
+	old := &insteon.Snapshot{
+		Version: insteon.SnapshotVersion,
+		Taken:   time.Now(),
+		Modem: &insteon.ModemSnapshot{Address: oldModem, Links: []*insteon.AllLinkRecord{
+			{Flags: 0xE2, Group: 1, Address: device},
+			{Flags: 0xE2, Group: 2, Address: gone},
+		}},
+		Devices: []*insteon.DeviceSnapshot{
+			{Address: device, Category: dimmer.Category, OperatingFlags: 0x10, Links: []*insteon.AllLinkRecord{
+				{Flags: 0xA2, Group: 1, Address: oldModem, Data: [3]byte{0xFF, 0x1C, 0x01}},
+			}},
+			{Address: gone, Category: dimmer.Category, Links: []*insteon.AllLinkRecord{
+				{Flags: 0xA2, Group: 2, Address: oldModem},
+			}},
+		},
+	}
+	cur := &insteon.Snapshot{
+		Version: insteon.SnapshotVersion,
+		Taken:   time.Now(),
+		Modem: &insteon.ModemSnapshot{Address: newModem, Links: []*insteon.AllLinkRecord{
+			{Flags: 0xE2, Group: 1, Address: device},
+		}},
+		Devices: []*insteon.DeviceSnapshot{
+			{Address: device, Category: dimmer.Category, OperatingFlags: 0x10, ExtendedConfig: &config,
+				Links: []*insteon.AllLinkRecord{
+					{Flags: 0xA2, Group: 1, Address: oldModem, Data: [3]byte{0x7F, 0x1C, 0x01}},
+				}},
+		},
+	}
+
+	diff := old.Diff(cur)
+	s.Require().False(diff.Empty())
+	s.Require().Equal([]*insteon.LinkChange{
+		{Owner: newModem, Kind: insteon.ModemOwner, Old: old.Modem.Links[1]},
+		{Owner: device, Kind: dimmer, Old: old.Devices[0].Links[0], New: cur.Devices[0].Links[0]},
+		{Owner: gone, Kind: dimmer, Old: old.Devices[1].Links[0]},
+	}, diff.Links)
+	s.Require().Equal([]*insteon.SettingChange{
+		{Owner: device, Setting: "ExtendedConfig", Old: "none", New: "00010000001C0000000000000000"},
+	}, diff.Settings)
+	s.Require().Equal("AA:BB:CC: changed Responder, Group=1, Address=01:02:03, OnLevel=FF, RampRate=1C, Button=1 to "+
+		"Responder, Group=1, Address=01:02:03, OnLevel=7F, RampRate=1C, Button=1", diff.Links[1].String())
+}
+
+func TestSnapshotTestSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SnapshotTestSuite))
+}
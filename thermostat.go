@@ -0,0 +1,392 @@
+package insteon
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// These are the commands understood by Insteon thermostats.
+const (
+	cmdThermostatGet             byte = 0x6A
+	cmdThermostatControl         byte = 0x6B
+	cmdThermostatSetCoolSetpoint byte = 0x6C
+	cmdThermostatSetHeatSetpoint byte = 0x6D
+	cmdThermostatTemperature     byte = 0x6E
+	cmdThermostatHumidity        byte = 0x6F
+	cmdThermostatModeChange      byte = 0x70
+	cmdThermostatCoolSetpoint    byte = 0x71
+	cmdThermostatHeatSetpoint    byte = 0x72
+)
+
+// These are the cmd2 values used with cmdThermostatGet and cmdThermostatControl.
+const (
+	thermostatGetTemperature byte = 0x00
+
+	thermostatControlHeat    byte = 0x04
+	thermostatControlCool    byte = 0x05
+	thermostatControlAuto    byte = 0x06
+	thermostatControlFanOn   byte = 0x07
+	thermostatControlFanAuto byte = 0x08
+	thermostatControlOff     byte = 0x09
+	thermostatControlProgram byte = 0x0A
+
+	thermostatStatusPage byte = 0x02
+)
+
+// These are the groups a thermostat broadcasts on.
+const (
+	thermostatGroupCooling       byte = 0x01
+	thermostatGroupHeating       byte = 0x02
+	thermostatGroupDehumidifying byte = 0x03
+	thermostatGroupHumidifying   byte = 0x04
+	thermostatGroupStatus        byte = 0xEF
+)
+
+// These bits are reported in the status flags of the extended status page.
+const (
+	thermostatFlagCooling      byte = 0x01
+	thermostatFlagHeating      byte = 0x02
+	thermostatFlagEnergySaving byte = 0x04
+	thermostatFlagCelsius      byte = 0x08
+	thermostatFlagHold         byte = 0x10
+)
+
+// ThermostatMode is the system mode of a thermostat.
+type ThermostatMode byte
+
+const (
+	ThermostatModeOff     ThermostatMode = 0x0
+	ThermostatModeAuto    ThermostatMode = 0x1
+	ThermostatModeHeat    ThermostatMode = 0x2
+	ThermostatModeCool    ThermostatMode = 0x3
+	ThermostatModeProgram ThermostatMode = 0x4
+)
+
+func (m ThermostatMode) String() string {
+	switch m {
+	case ThermostatModeOff:
+		return "Off"
+	case ThermostatModeAuto:
+		return "Auto"
+	case ThermostatModeHeat:
+		return "Heat"
+	case ThermostatModeCool:
+		return "Cool"
+	case ThermostatModeProgram:
+		return "Program"
+	default:
+		return "Unknown"
+	}
+}
+
+// ThermostatFanMode is the fan mode of a thermostat.
+type ThermostatFanMode byte
+
+const (
+	ThermostatFanModeAuto ThermostatFanMode = 0x0
+	ThermostatFanModeOn   ThermostatFanMode = 0x1
+)
+
+func (m ThermostatFanMode) String() string {
+	switch m {
+	case ThermostatFanModeAuto:
+		return "Auto"
+	case ThermostatFanModeOn:
+		return "On"
+	default:
+		return "Unknown"
+	}
+}
+
+// TemperatureUnits are the units a thermostat reports temperatures and setpoints in.
+type TemperatureUnits int
+
+const (
+	TemperatureUnitsFahrenheit TemperatureUnits = iota
+	TemperatureUnitsCelsius
+)
+
+func (u TemperatureUnits) String() string {
+	if u == TemperatureUnitsCelsius {
+		return "C"
+	}
+
+	return "F"
+}
+
+// ThermostatStatus is a snapshot of a thermostat's state read from its extended status page. All temperatures are
+// in the thermostat's configured units.
+type ThermostatStatus struct {
+	// Day, Hour and Minute are the thermostat's clock.
+	Day    time.Weekday
+	Hour   int
+	Minute int
+	// Temperature is the current ambient temperature.
+	Temperature float64
+	// Humidity is the current relative humidity in percent.
+	Humidity     int
+	CoolSetpoint int
+	HeatSetpoint int
+	Mode         ThermostatMode
+	FanMode      ThermostatFanMode
+	Units        TemperatureUnits
+	Cooling      bool
+	Heating      bool
+	EnergySaving bool
+	Hold         bool
+}
+
+func (s *ThermostatStatus) String() string {
+	return fmt.Sprintf("Temperature=%.1f%s, Humidity=%d%%, Cool=%d, Heat=%d, Mode=%s, Fan=%s, Cooling=%t, Heating=%t",
+		s.Temperature, s.Units, s.Humidity, s.CoolSetpoint, s.HeatSetpoint, s.Mode, s.FanMode, s.Cooling, s.Heating)
+}
+
+// fromBytes parses the data of the extended status page.
+func (s *ThermostatStatus) fromBytes(data []byte) {
+	s.Day = time.Weekday(data[1])
+	s.Hour = int(data[2])
+	s.Minute = int(data[3])
+	s.CoolSetpoint = int(data[4])
+	s.Humidity = int(data[5])
+	s.Mode = ThermostatMode(data[8] >> 4)
+	s.FanMode = ThermostatFanMode(data[8] & 0xF)
+	s.Cooling = data[9]&thermostatFlagCooling > 0
+	s.Heating = data[9]&thermostatFlagHeating > 0
+	s.EnergySaving = data[9]&thermostatFlagEnergySaving > 0
+	s.Hold = data[9]&thermostatFlagHold > 0
+	s.HeatSetpoint = int(data[10])
+
+	// The ambient temperature is always reported in tenths of a degree Celsius.
+	celsius := float64(uint16(data[6])<<8+uint16(data[7])) / 10
+	s.Temperature = celsius
+	s.Units = TemperatureUnitsCelsius
+
+	if data[9]&thermostatFlagCelsius == 0 {
+		s.Units = TemperatureUnitsFahrenheit
+		s.Temperature = math.Round((celsius*9/5+32)*10) / 10
+	}
+}
+
+// ThermostatEventType describes what changed in a ThermostatEvent.
+type ThermostatEventType int
+
+const (
+	ThermostatEventTemperature ThermostatEventType = iota
+	ThermostatEventHumidity
+	ThermostatEventMode
+	ThermostatEventCoolSetpoint
+	ThermostatEventHeatSetpoint
+	ThermostatEventCooling
+	ThermostatEventHeating
+	ThermostatEventDehumidifying
+	ThermostatEventHumidifying
+)
+
+func (t ThermostatEventType) String() string {
+	switch t {
+	case ThermostatEventTemperature:
+		return "Temperature"
+	case ThermostatEventHumidity:
+		return "Humidity"
+	case ThermostatEventMode:
+		return "Mode"
+	case ThermostatEventCoolSetpoint:
+		return "Cool Setpoint"
+	case ThermostatEventHeatSetpoint:
+		return "Heat Setpoint"
+	case ThermostatEventCooling:
+		return "Cooling"
+	case ThermostatEventHeating:
+		return "Heating"
+	case ThermostatEventDehumidifying:
+		return "Dehumidifying"
+	case ThermostatEventHumidifying:
+		return "Humidifying"
+	default:
+		return "Unknown"
+	}
+}
+
+// ThermostatEvent is generated when a thermostat reports a change without being asked.
+type ThermostatEvent struct {
+	deviceEvent
+	Type ThermostatEventType
+	// Value holds the new temperature, humidity or setpoint.
+	Value float64
+	// Mode and FanMode hold the new modes for ThermostatEventMode.
+	Mode    ThermostatMode
+	FanMode ThermostatFanMode
+	// On holds the new state for the cooling, heating, dehumidifying and humidifying events.
+	On bool
+}
+
+// Thermostat represents an Insteon thermostat such as the 2441TH, 2441ZTH or 2732.
+type Thermostat struct {
+	*Device
+	events deviceEvents
+}
+
+// NewThermostat creates a new thermostat by raw address.
+func NewThermostat(hub Hub, addr Address) (*Thermostat, error) {
+	dev, err := NewDevice(hub, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Thermostat{Device: dev}, nil
+}
+
+// GetThermostatStatus reads the thermostat's extended status page.
+func (t *Thermostat) GetThermostatStatus(ctx context.Context) (*ThermostatStatus, error) {
+	if _, err := t.sendExtended(ctx, cmdControlExtSetGet, thermostatStatusPage, [14]byte{}); err != nil {
+		return nil, err
+	}
+
+	for {
+		rsp, err := t.expectExtended(ctx, cmdControlExtSetGet)
+		if err != nil {
+			return nil, err
+		}
+
+		if rsp.Cmd2() == thermostatStatusPage {
+			status := &ThermostatStatus{}
+			status.fromBytes(rsp.data[:])
+
+			return status, nil
+		}
+	}
+}
+
+// GetTemperature reads just the ambient temperature, in the thermostat's configured units.
+func (t *Thermostat) GetTemperature(ctx context.Context) (float64, error) {
+	rsp, err := t.sendMessage(ctx, cmdThermostatGet, thermostatGetTemperature)
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(rsp.Cmd2()) / 2, nil
+}
+
+// SetMode changes the system mode of the thermostat.
+func (t *Thermostat) SetMode(ctx context.Context, mode ThermostatMode) error {
+	var cmd2 byte
+
+	switch mode {
+	case ThermostatModeOff:
+		cmd2 = thermostatControlOff
+	case ThermostatModeAuto:
+		cmd2 = thermostatControlAuto
+	case ThermostatModeHeat:
+		cmd2 = thermostatControlHeat
+	case ThermostatModeCool:
+		cmd2 = thermostatControlCool
+	case ThermostatModeProgram:
+		cmd2 = thermostatControlProgram
+	default:
+		return fmt.Errorf("unknown thermostat mode: %d", mode)
+	}
+
+	_, err := t.sendMessage(ctx, cmdThermostatControl, cmd2)
+
+	return err
+}
+
+// SetFanMode changes the fan mode of the thermostat.
+func (t *Thermostat) SetFanMode(ctx context.Context, mode ThermostatFanMode) error {
+	cmd2 := thermostatControlFanAuto
+	if mode == ThermostatFanModeOn {
+		cmd2 = thermostatControlFanOn
+	}
+
+	_, err := t.sendMessage(ctx, cmdThermostatControl, cmd2)
+
+	return err
+}
+
+// SetCoolSetpoint sets the cooling setpoint in the thermostat's configured units.
+func (t *Thermostat) SetCoolSetpoint(ctx context.Context, temp int) error {
+	return t.setSetpoint(ctx, cmdThermostatSetCoolSetpoint, temp)
+}
+
+// SetHeatSetpoint sets the heating setpoint in the thermostat's configured units.
+func (t *Thermostat) SetHeatSetpoint(ctx context.Context, temp int) error {
+	return t.setSetpoint(ctx, cmdThermostatSetHeatSetpoint, temp)
+}
+
+func (t *Thermostat) setSetpoint(ctx context.Context, cmd1 byte, temp int) error {
+	// Setpoints are sent in half degree increments.
+	const maxSetpoint = 0xFF / 2
+
+	if temp < 0 || temp > maxSetpoint {
+		return fmt.Errorf("setpoint out of range: %d", temp)
+	}
+
+	_, err := t.sendMessage(ctx, cmd1, byte(temp*2))
+
+	return err
+}
+
+// AddListener registers a listener that's notified with a ThermostatEvent whenever the thermostat reports a change.
+// Changes are only reported if the modem is linked to the thermostat's groups.
+func (t *Thermostat) AddListener(listener DeviceEventListener) {
+	t.events.add(listener)
+	t.events.watch(t.hub, t.address, t.handleMessage)
+}
+
+func (t *Thermostat) handleMessage(rsp CommandResponse) {
+	evt := &ThermostatEvent{deviceEvent: deviceEvent{from: t.address}}
+
+	if group, ok := groupBroadcast(rsp); ok && group != thermostatGroupStatus {
+		switch group {
+		case thermostatGroupCooling:
+			evt.Type = ThermostatEventCooling
+		case thermostatGroupHeating:
+			evt.Type = ThermostatEventHeating
+		case thermostatGroupDehumidifying:
+			evt.Type = ThermostatEventDehumidifying
+		case thermostatGroupHumidifying:
+			evt.Type = ThermostatEventHumidifying
+		default:
+			return
+		}
+
+		switch rsp.Cmd1() {
+		case cmdControlOn:
+			evt.On = true
+		case cmdControlOff:
+			evt.On = false
+		default:
+			return
+		}
+
+		t.events.emit(evt)
+
+		return
+	}
+
+	// Everything else is reported to the modem as a direct message on the status group.
+	switch rsp.Cmd1() {
+	case cmdThermostatTemperature:
+		evt.Type = ThermostatEventTemperature
+		evt.Value = float64(rsp.Cmd2()) / 2
+	case cmdThermostatHumidity:
+		evt.Type = ThermostatEventHumidity
+		evt.Value = float64(rsp.Cmd2())
+	case cmdThermostatModeChange:
+		evt.Type = ThermostatEventMode
+		evt.Mode = ThermostatMode(rsp.Cmd2() >> 4)
+		evt.FanMode = ThermostatFanMode(rsp.Cmd2() & 0xF)
+	case cmdThermostatCoolSetpoint:
+		evt.Type = ThermostatEventCoolSetpoint
+		evt.Value = float64(rsp.Cmd2())
+	case cmdThermostatHeatSetpoint:
+		evt.Type = ThermostatEventHeatSetpoint
+		evt.Value = float64(rsp.Cmd2())
+	default:
+		return
+	}
+
+	t.events.emit(evt)
+}
@@ -0,0 +1,104 @@
+package insteon_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/swedishborgie/go-insteon"
+)
+
+type ThermostatTestSuite struct {
+	suite.Suite
+	mock       *InsteonHubMock
+	thermostat *insteon.Thermostat
+}
+
+func (s *ThermostatTestSuite) SetupTest() {
+	var hub insteon.Hub
+
+	hub, s.mock = newMock()
+
+	var err error
+
+	s.thermostat, err = insteon.NewThermostat(hub, insteon.Address{0xAA, 0xBB, 0xCC})
+	s.Require().NoError(err)
+}
+
+func (s *ThermostatTestSuite) TestGetThermostatStatus() {
+	s.mock.ExpectEngineVersion(0x01)
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xCE},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x3F, 0x2E, 0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xCE, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x2E, 0x02,
+			0x02, 0x51, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x1B, 0x2E, 0x02,
+			0x01, 0x03, 0x0E, 0x1E, 0x4C, 0x2D, 0x00, 0xDC, 0x31, 0x01, 0x44, 0x00, 0x00, 0x00,
+		},
+	)
+
+	status, err := s.thermostat.GetThermostatStatus(s.mock.ctx)
+	s.Require().NoError(err)
+	s.Require().Equal(time.Wednesday, status.Day)
+	s.Require().Equal(14, status.Hour)
+	s.Require().Equal(30, status.Minute)
+	s.Require().Equal(76, status.CoolSetpoint)
+	s.Require().Equal(68, status.HeatSetpoint)
+	s.Require().Equal(45, status.Humidity)
+	s.Require().InDelta(71.6, status.Temperature, 0.01)
+	s.Require().Equal(insteon.TemperatureUnitsFahrenheit, status.Units)
+	s.Require().Equal(insteon.ThermostatModeCool, status.Mode)
+	s.Require().Equal(insteon.ThermostatFanModeOn, status.FanMode)
+	s.Require().True(status.Cooling)
+	s.Require().False(status.Heating)
+}
+
+func (s *ThermostatTestSuite) TestSetHeatSetpoint() {
+	s.mock.Expect(
+		[]byte{0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x6D, 0x88},
+		[]byte{
+			0x02, 0x62, 0xAA, 0xBB, 0xCC, 0x0F, 0x6D, 0x88, 0x06,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x2B, 0x6D, 0x88,
+		},
+	)
+
+	s.Require().NoError(s.thermostat.SetHeatSetpoint(s.mock.ctx, 68))
+}
+
+func (s *ThermostatTestSuite) TestEvents() {
+	events := make(chan *insteon.ThermostatEvent, 2)
+
+	s.thermostat.AddListener(func(evt insteon.DeviceEvent) {
+		events <- evt.(*insteon.ThermostatEvent)
+	})
+
+	go func() {
+		_, _ = s.mock.outPipeOut.Write([]byte{
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x00, 0x00, 0x02, 0xCF, 0x11, 0x00,
+			0x02, 0x50, 0xAA, 0xBB, 0xCC, 0x01, 0x02, 0x03, 0x0F, 0x6E, 0x8E,
+		})
+	}()
+
+	evts := map[insteon.ThermostatEventType]*insteon.ThermostatEvent{}
+
+	for len(evts) < 2 {
+		var evt *insteon.ThermostatEvent
+
+		select {
+		case evt = <-events:
+		case <-time.After(time.Second):
+			s.FailNow("event wasn't reported")
+		}
+
+		evts[evt.Type] = evt
+	}
+
+	s.Require().True(evts[insteon.ThermostatEventHeating].On)
+	s.Require().InDelta(71.0, evts[insteon.ThermostatEventTemperature].Value, 0.01)
+}
+
+func TestThermostatSuite(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &ThermostatTestSuite{})
+}